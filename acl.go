@@ -0,0 +1,357 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"sync"
+	"time"
+)
+
+// Who may invoke a command or interaction. Channels and EnterpriseIDs restrict where a request may come from; Users,
+// UserGroups, Admins and Owners grant access to whoever matches any of them. A policy granting no one allows every
+// user in the allowed channels and enterprises.
+type Policy struct {
+	// User IDs allowed
+	Users []string
+	// User group IDs whose members are allowed, resolved with usergroups.users.list which needs the usergroups:read scope
+	UserGroups []string
+	// Allow workspace admins and owners, resolved with users.info which needs the users:read scope
+	Admins bool
+	// Allow workspace owners
+	Owners bool
+	// Channel IDs requests must come from
+	Channels []string
+	// Enterprise Grid organization IDs requests must come from
+	EnterpriseIDs []string
+	// Ephemeral message answering denied requests. Defaults to "Sorry, you are not allowed to do that."
+	DeniedMessage string
+}
+
+func (p Policy) grants() bool {
+	return len(p.Users) > 0 || len(p.UserGroups) > 0 || p.Admins || p.Owners
+}
+
+// A denied request, passed to ACLOptions.Audit
+type AuditEntry struct {
+	Time         time.Time
+	TeamID       string
+	EnterpriseID string
+	UserID       string
+	ChannelID    string
+	// "command" or the interaction type
+	Kind string
+	// Name of the command, action ID or callback ID the policy was set for
+	Name string
+	// Why the request was denied
+	Reason string
+}
+
+// Records a denied request
+type AuditCallback = func(entry AuditEntry)
+
+// Options for OptionACL
+type ACLOptions struct {
+	// How long user group members and user roles are cached. Defaults to 5 minutes.
+	CacheTTL time.Duration
+	// Called for every denied request, in addition to the request being logged
+	Audit AuditCallback
+}
+
+// accessRequest describes who a request came from and where
+type accessRequest struct {
+	TeamID       string
+	EnterpriseID string
+	UserID       string
+	ChannelID    string
+	Kind         string
+	Name         string
+}
+
+type cachedMembers struct {
+	members []string
+	expires time.Time
+}
+
+type cachedRole struct {
+	admin, owner bool
+	expires      time.Time
+}
+
+type acl struct {
+	options   ACLOptions
+	commands  map[string]Policy
+	actions   map[string]Policy
+	callbacks map[string]Policy
+	members   map[string]cachedMembers
+	roles     map[string]cachedRole
+	cacheLock sync.Mutex
+}
+
+func newACL(options ACLOptions) *acl {
+	if options.CacheTTL <= 0 {
+		options.CacheTTL = 5 * time.Minute
+	}
+	return &acl{
+		options:   options,
+		commands:  make(map[string]Policy),
+		actions:   make(map[string]Policy),
+		callbacks: make(map[string]Policy),
+		members:   make(map[string]cachedMembers),
+		roles:     make(map[string]cachedRole),
+	}
+}
+
+// aclLocked returns the bot's ACL, creating it, and must be called with the write lock held
+func (b *Bot) aclLocked() *acl {
+	if b.acl == nil {
+		b.acl = newACL(ACLOptions{})
+	}
+	return b.acl
+}
+
+// Restrict who may invoke a slash command
+func (b *Bot) SetCommandPolicy(name string, policy Policy) {
+	b.logger().Debug("Set command policy", Fields{"command": name})
+
+	b.Lock()
+	defer b.Unlock()
+	b.aclLocked().commands[commandName(name)] = policy
+}
+
+// Restrict who may invoke block actions with an action ID. An interaction is denied when any of its actions is.
+func (b *Bot) SetActionPolicy(actionID string, policy Policy) {
+	b.logger().Debug("Set action policy", Fields{"action_id": actionID})
+
+	b.Lock()
+	defer b.Unlock()
+	b.aclLocked().actions[actionID] = policy
+}
+
+// Restrict who may invoke shortcuts, message actions, view submissions and closed views with a callback ID.
+// A denied view submission closes the view.
+func (b *Bot) SetCallbackPolicy(callbackID string, policy Policy) {
+	b.logger().Debug("Set callback policy", Fields{"callback_id": callbackID})
+
+	b.Lock()
+	defer b.Unlock()
+	b.aclLocked().callbacks[callbackID] = policy
+}
+
+// authorizeCommand returns the ephemeral message answering a denied command, or nil when it is allowed
+func (b *Bot) authorizeCommand(ctx context.Context, name string, command slack.SlashCommand) *slack.Msg {
+	name = commandName(name)
+	b.RLock()
+	var policy Policy
+	exists := false
+	if b.acl != nil {
+		policy, exists = b.acl.commands[name]
+	}
+	b.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	request := accessRequest{
+		TeamID:       command.TeamID,
+		EnterpriseID: command.EnterpriseID,
+		UserID:       command.UserID,
+		ChannelID:    command.ChannelID,
+		Kind:         "command",
+		Name:         name,
+	}
+	if b.authorize(ctx, policy, request) {
+		return nil
+	}
+	return ephemeral(policy.deniedMessage())
+}
+
+// authorizeInteraction checks every policy an interaction falls under, answering it with an ephemeral message where
+// it came from when it is denied
+func (b *Bot) authorizeInteraction(ctx context.Context, interaction slack.InteractionCallback, payload []byte) bool {
+	b.RLock()
+	var policies []Policy
+	var names []string
+	if b.acl != nil {
+		if policy, exists := b.acl.callbacks[interactionCallbackID(interaction)]; exists {
+			policies = append(policies, policy)
+			names = append(names, interactionCallbackID(interaction))
+		}
+		for _, action := range interaction.ActionCallback.BlockActions {
+			if policy, exists := b.acl.actions[action.ActionID]; exists {
+				policies = append(policies, policy)
+				names = append(names, action.ActionID)
+			}
+		}
+	}
+	b.RUnlock()
+	if len(policies) == 0 {
+		return true
+	}
+
+	// the enterprise of an interaction is not part of slack.InteractionCallback
+	var enterprise struct {
+		Enterprise struct {
+			ID string `json:"id"`
+		} `json:"enterprise"`
+	}
+	_ = json.Unmarshal(payload, &enterprise)
+
+	request := accessRequest{
+		TeamID:       interaction.Team.ID,
+		EnterpriseID: enterprise.Enterprise.ID,
+		UserID:       interaction.User.ID,
+		ChannelID:    interaction.Channel.ID,
+		Kind:         string(interaction.Type),
+	}
+	if request.ChannelID == "" {
+		request.ChannelID = interaction.Container.ChannelID
+	}
+	for i, policy := range policies {
+		request.Name = names[i]
+		if !b.authorize(ctx, policy, request) {
+			b.notifyDenied(ctx, interaction, request, policy.deniedMessage())
+			return false
+		}
+	}
+	return true
+}
+
+func (p Policy) deniedMessage() string {
+	if p.DeniedMessage == "" {
+		return "Sorry, you are not allowed to do that."
+	}
+	return p.DeniedMessage
+}
+
+// notifyDenied tells the user an interaction was denied through its response_url, or in its channel. Interactions
+// from neither, such as global shortcuts, are denied silently.
+func (b *Bot) notifyDenied(ctx context.Context, interaction slack.InteractionCallback, request accessRequest, text string) {
+	var err error
+	switch {
+	case interaction.ResponseURL != "":
+		err = b.RespondContext(ctx, interaction.ResponseURL, ephemeral(text))
+	case request.ChannelID != "":
+		_, err = b.ApiForTeam(request.TeamID).PostEphemeralContext(ctx, request.ChannelID, request.UserID, slack.MsgOptionText(text, false))
+	}
+	if err != nil {
+		b.logger().Error("Failed to notify denied user", Fields{"team_id": request.TeamID, "user_id": request.UserID, "error": err})
+	}
+}
+
+// authorize checks a request against a policy, recording it in the audit log when it is denied.
+// Requests are denied when the user's groups or role cannot be resolved.
+func (b *Bot) authorize(ctx context.Context, policy Policy, request accessRequest) bool {
+	reason, err := b.deniedReason(ctx, policy, request)
+	if err != nil {
+		b.logger().Error("Failed to check policy", Fields{"team_id": request.TeamID, "user_id": request.UserID, "error": err})
+		reason = "policy check failed"
+	}
+	if reason == "" {
+		return true
+	}
+
+	b.logger().Warn("Access denied", Fields{
+		"team_id":       request.TeamID,
+		"enterprise_id": request.EnterpriseID,
+		"user_id":       request.UserID,
+		"channel_id":    request.ChannelID,
+		"kind":          request.Kind,
+		"name":          request.Name,
+		"reason":        reason,
+	})
+	b.RLock()
+	audit := b.acl.options.Audit
+	b.RUnlock()
+	if audit != nil {
+		audit(AuditEntry{
+			Time:         time.Now(),
+			TeamID:       request.TeamID,
+			EnterpriseID: request.EnterpriseID,
+			UserID:       request.UserID,
+			ChannelID:    request.ChannelID,
+			Kind:         request.Kind,
+			Name:         request.Name,
+			Reason:       reason,
+		})
+	}
+	return false
+}
+
+// deniedReason returns why a policy denies a request, or an empty string when it allows it
+func (b *Bot) deniedReason(ctx context.Context, policy Policy, request accessRequest) (string, error) {
+	if len(policy.EnterpriseIDs) > 0 && !contains(policy.EnterpriseIDs, request.EnterpriseID) {
+		return "enterprise not allowed", nil
+	}
+	if len(policy.Channels) > 0 && !contains(policy.Channels, request.ChannelID) {
+		return "channel not allowed", nil
+	}
+	if !policy.grants() || contains(policy.Users, request.UserID) {
+		return "", nil
+	}
+
+	if policy.Admins || policy.Owners {
+		admin, owner, err := b.userRole(ctx, request.TeamID, request.UserID)
+		if err != nil {
+			return "", err
+		}
+		if owner || (policy.Admins && admin) {
+			return "", nil
+		}
+	}
+	for _, group := range policy.UserGroups {
+		members, err := b.userGroupMembers(ctx, request.TeamID, group)
+		if err != nil {
+			return "", err
+		}
+		if contains(members, request.UserID) {
+			return "", nil
+		}
+	}
+	return "user not allowed", nil
+}
+
+func (b *Bot) userRole(ctx context.Context, teamID string, userID string) (admin bool, owner bool, err error) {
+	a := b.acl
+	key := teamID + "/" + userID
+	a.cacheLock.Lock()
+	cached, exists := a.roles[key]
+	a.cacheLock.Unlock()
+	if exists && time.Now().Before(cached.expires) {
+		return cached.admin, cached.owner, nil
+	}
+
+	user, err := b.ApiForTeam(teamID).GetUserInfoContext(ctx, userID)
+	if err != nil {
+		return false, false, err
+	}
+	cached = cachedRole{
+		admin:   user.IsAdmin,
+		owner:   user.IsOwner || user.IsPrimaryOwner,
+		expires: time.Now().Add(a.options.CacheTTL),
+	}
+	a.cacheLock.Lock()
+	a.roles[key] = cached
+	a.cacheLock.Unlock()
+	return cached.admin, cached.owner, nil
+}
+
+func (b *Bot) userGroupMembers(ctx context.Context, teamID string, groupID string) ([]string, error) {
+	a := b.acl
+	key := teamID + "/" + groupID
+	a.cacheLock.Lock()
+	cached, exists := a.members[key]
+	a.cacheLock.Unlock()
+	if exists && time.Now().Before(cached.expires) {
+		return cached.members, nil
+	}
+
+	members, err := b.ApiForTeam(teamID).GetUserGroupMembersContext(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	a.cacheLock.Lock()
+	a.members[key] = cachedMembers{members: members, expires: time.Now().Add(a.options.CacheTTL)}
+	a.cacheLock.Unlock()
+	return members, nil
+}
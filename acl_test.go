@@ -0,0 +1,173 @@
+package slackbot
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// newACLSlack answers usergroups.users.list with U2 in S1 and users.info with U3 as an admin
+func newACLSlack() *fakeSlack {
+	server := newFakeSlack()
+	server.setResponseFunc("usergroups.users.list", func(call apiCall) interface{} {
+		if call.form.Get("usergroup") != "S1" {
+			return map[string]interface{}{"ok": false, "error": "no_such_subteam"}
+		}
+		return map[string]interface{}{"ok": true, "users": []string{"U2"}}
+	})
+	server.setResponseFunc("users.info", func(call apiCall) interface{} {
+		user := call.form.Get("user")
+		return map[string]interface{}{"ok": true, "user": map[string]interface{}{"id": user, "is_admin": user == "U3"}}
+	})
+	return server
+}
+
+// lookups counts the calls resolving user groups and roles
+func lookups(server *fakeSlack) int {
+	return len(server.callsTo("usergroups.users.list")) + len(server.callsTo("users.info"))
+}
+
+func postPolicyCommand(t *testing.T, engine *gin.Engine, userID string, channelID string, enterpriseID string) string {
+	e := getHttpExpect(t, engine)
+	return e.POST("/slack/commands").
+		WithFormField("command", "/deploy").
+		WithFormField("team_id", "T1").
+		WithFormField("enterprise_id", enterpriseID).
+		WithFormField("user_id", userID).
+		WithFormField("channel_id", channelID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("text").String().Raw()
+}
+
+func TestSetCommandPolicy(t *testing.T) {
+	server := newACLSlack()
+	defer server.Close()
+
+	var audited []AuditEntry
+	engine := gin.New()
+	bot := server.newBot(OptionACL(ACLOptions{Audit: func(entry AuditEntry) {
+		audited = append(audited, entry)
+	}}))
+	bot.RegisterCommand("deploy", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "deploying"}
+	})
+	bot.SetCommandPolicy("/deploy", Policy{Users: []string{"U1"}, UserGroups: []string{"S1"}, Admins: true, DeniedMessage: "Ask #ops."})
+	bot.prepareEngine(engine, false)
+
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U1", "C1", ""))
+	assert.Equal(t, 0, lookups(server))
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U2", "C1", ""))
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U3", "C1", ""))
+	assert.Equal(t, "Ask #ops.", postPolicyCommand(t, engine, "U4", "C1", ""))
+
+	if assert.Equal(t, 1, len(audited)) {
+		assert.Equal(t, "U4", audited[0].UserID)
+		assert.Equal(t, "command", audited[0].Kind)
+		assert.Equal(t, "deploy", audited[0].Name)
+		assert.Equal(t, "user not allowed", audited[0].Reason)
+	}
+
+	// roles and group members are cached
+	lookupsBefore := lookups(server)
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U2", "C1", ""))
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U3", "C1", ""))
+	assert.Equal(t, lookupsBefore, lookups(server))
+}
+
+func TestPolicyRestrictions(t *testing.T) {
+	server := newACLSlack()
+	defer server.Close()
+
+	var audited []AuditEntry
+	engine := gin.New()
+	bot := server.newBot(OptionACL(ACLOptions{Audit: func(entry AuditEntry) {
+		audited = append(audited, entry)
+	}}))
+	bot.RegisterCommand("deploy", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "deploying"}
+	})
+	bot.SetCommandPolicy("deploy", Policy{Channels: []string{"C1"}, EnterpriseIDs: []string{"E1"}})
+	bot.prepareEngine(engine, false)
+
+	denied := "Sorry, you are not allowed to do that."
+	assert.Equal(t, "deploying", postPolicyCommand(t, engine, "U1", "C1", "E1"))
+	assert.Equal(t, denied, postPolicyCommand(t, engine, "U1", "C2", "E1"))
+	assert.Equal(t, denied, postPolicyCommand(t, engine, "U1", "C1", "E2"))
+
+	if assert.Equal(t, 2, len(audited)) {
+		assert.Equal(t, "channel not allowed", audited[0].Reason)
+		assert.Equal(t, "enterprise not allowed", audited[1].Reason)
+	}
+}
+
+func TestPolicyFailsClosed(t *testing.T) {
+	server := newACLSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterCommand("deploy", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "deploying"}
+	})
+	bot.SetCommandPolicy("deploy", Policy{UserGroups: []string{"S2"}})
+	bot.prepareEngine(engine, false)
+
+	assert.Equal(t, "Sorry, you are not allowed to do that.", postPolicyCommand(t, engine, "U2", "C1", ""))
+}
+
+func TestSetActionPolicy(t *testing.T) {
+	server := newACLSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	var approved []string
+	bot.RegisterBlockAction(BlockActionFilter{ActionID: "approve"}, func(bot *Bot, interaction slack.InteractionCallback, action *slack.BlockAction) {
+		approved = append(approved, interaction.User.ID)
+	})
+	bot.SetActionPolicy("approve", Policy{Users: []string{"U1"}})
+	bot.prepareEngine(engine, false)
+
+	approve := func(userID string) {
+		postInteraction(t, engine, slack.InteractionCallback{
+			Type:           slack.InteractionTypeBlockActions,
+			Team:           slack.Team{ID: "T1"},
+			User:           slack.User{ID: userID},
+			ResponseURL:    server.responseURL(),
+			ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "approve"}}},
+		})
+	}
+	approve("U1")
+	approve("U2")
+
+	assert.Equal(t, []string{"U1"}, approved)
+	responded := server.responded()
+	if assert.Equal(t, 1, len(responded)) {
+		assert.Equal(t, slack.ResponseTypeEphemeral, responded[0].ResponseType)
+		assert.Equal(t, "Sorry, you are not allowed to do that.", responded[0].Text)
+	}
+}
+
+func TestSetCallbackPolicy(t *testing.T) {
+	engine := gin.New()
+	bot := newBot()
+	var opened []string
+	bot.RegisterShortcutInteraction("admin-tools", func(bot *Bot, interaction slack.InteractionCallback) {
+		opened = append(opened, interaction.User.ID)
+	})
+	bot.SetCallbackPolicy("admin-tools", Policy{Users: []string{"U1"}})
+	bot.prepareEngine(engine, false)
+
+	for _, userID := range []string{"U1", "U2"} {
+		postInteraction(t, engine, slack.InteractionCallback{
+			Type:       slack.InteractionTypeShortcut,
+			CallbackID: "admin-tools",
+			User:       slack.User{ID: userID},
+		})
+	}
+
+	assert.Equal(t, []string{"U1"}, opened)
+}
@@ -0,0 +1,109 @@
+package slackbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/slack-go/slack"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Get a slack.Client for interacting with the API using the bot token.
+// The client is created once and shared, and uses the bot's configured API URL, HTTP client and slack.Options.
+func (b *Bot) Api() *slack.Client {
+	return b.ApiForToken(b.token)
+}
+
+// Get a slack.Client for interacting with the API using another token, such as a user token.
+// One client is created and shared per token.
+func (b *Bot) ApiForToken(token string) *slack.Client {
+	b.clientsLock.Lock()
+	defer b.clientsLock.Unlock()
+
+	if b.clients == nil {
+		b.clients = make(map[string]*slack.Client)
+	}
+	client, exists := b.clients[token]
+	if !exists {
+		client = slack.New(token, b.clientOptions()...)
+		b.clients[token] = client
+	}
+	return client
+}
+
+// Get a slack.Client for a team, using the token from OptionTeamTokens or the bot token when there is none
+func (b *Bot) ApiForTeam(teamID string) *slack.Client {
+	if b.teamTokens != nil {
+		if token := b.teamTokens(teamID); token != "" {
+			return b.ApiForToken(token)
+		}
+	}
+	return b.Api()
+}
+
+func (b *Bot) clientOptions() []slack.Option {
+	options := []slack.Option{slack.OptionHTTPClient(b.HTTPClient())}
+	if b.apiURL != "" {
+		options = append(options, slack.OptionAPIURL(b.apiURL))
+	}
+	return append(options, b.slackOptions...)
+}
+
+// webAPIURL returns the base URL of the Web API methods the bot calls
+func (b *Bot) webAPIURL() string {
+	if b.apiURL == "" {
+		return slack.APIURL
+	}
+	return b.apiURL
+}
+
+// apiMethod names the Web API method a request calls. Requests outside the Web API, such as posts to response URLs
+// whose paths are unique and secret, are all named "response_url".
+func apiMethod(request *http.Request, apiURL string) string {
+	if !strings.HasPrefix(request.URL.String(), apiURL) {
+		return "response_url"
+	}
+	return path.Base(request.URL.Path)
+}
+
+// Get the *http.Client used for all outbound requests to Slack
+func (b *Bot) HTTPClient() *http.Client {
+	if b.httpClient == nil {
+		return http.DefaultClient
+	}
+	return b.httpClient
+}
+
+// Post a message to a response_url from a slash command or interaction
+func (b *Bot) Respond(responseURL string, msg *slack.Msg) error {
+	return b.RespondContext(context.Background(), responseURL, msg)
+}
+
+// Post a message to a response_url from a slash command or interaction with a custom context
+func (b *Bot) RespondContext(ctx context.Context, responseURL string, msg *slack.Msg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := b.HTTPClient().Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("response_url returned status %d", response.StatusCode)
+	}
+	return nil
+}
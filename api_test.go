@@ -0,0 +1,78 @@
+package slackbot
+
+import (
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestApiIsCached(t *testing.T) {
+	bot := newBot()
+
+	assert.Same(t, bot.Api(), bot.Api())
+	assert.Same(t, bot.Api(), bot.ApiForToken("token"))
+	assert.False(t, bot.Api() == bot.ApiForToken("other"))
+	assert.Same(t, bot.ApiForToken("other"), bot.ApiForToken("other"))
+}
+
+func TestApiUsesConfiguredTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth.test", r.URL.Path)
+		_, _ = w.Write([]byte(`{"ok": true, "user_id": "U1"}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	bot := NewBot("token", "secret",
+		OptionAPIURL(server.URL+"/api/"),
+		OptionHTTPClient(&http.Client{Transport: transport}),
+	)
+
+	response, err := bot.Api().AuthTest()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "U1", response.UserID)
+	assert.Equal(t, 1, transport.count)
+}
+
+func TestRespond(t *testing.T) {
+	var received slack.Msg
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	bot := NewBot("token", "secret", OptionHTTPClient(&http.Client{Transport: transport}))
+
+	err := bot.Respond(server.URL, &slack.Msg{Text: "hello", ReplaceOriginal: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", received.Text)
+	assert.True(t, received.ReplaceOriginal)
+	assert.Equal(t, 1, transport.count)
+}
+
+func TestRespondReturnsErrorOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := newBot().Respond(server.URL, &slack.Msg{Text: "hello"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"sync/atomic"
+	"time"
+)
+
+type AppRateLimitedCallback = func(bot *Bot, event AppRateLimitedEvent)
+
+// Sent by Slack when it stops delivering events to the app for the rest of a minute
+type AppRateLimitedEvent struct {
+	TeamID   string
+	APIAppID string
+	// Start of the minute during which events are not delivered
+	WindowStart time.Time
+	// End of the minute during which events are not delivered
+	WindowEnd time.Time
+}
+
+// Options for OptionBackpressure
+type BackpressureOptions struct {
+	// How long the bot stays throttled after an app_rate_limited event. Defaults to the end of the rate limited minute.
+	PauseFor time.Duration
+	// Channel which is told once per rate limited minute that Slack is dropping events. No alert is posted when empty.
+	AlertChannel string
+}
+
+func newAppRateLimitedEvent(event slackevents.EventsAPIAppRateLimited) AppRateLimitedEvent {
+	start := time.Unix(int64(event.MinuteRateLimited), 0)
+	return AppRateLimitedEvent{
+		TeamID:      event.TeamID,
+		APIAppID:    event.APIAppID,
+		WindowStart: start,
+		WindowEnd:   start.Add(time.Minute),
+	}
+}
+
+// Register a callback for when Slack rate limits the events sent to the app
+func (b *Bot) RegisterAppRateLimited(callback AppRateLimitedCallback) Handle {
+	return b.registerEvent(slackevents.AppRateLimited, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		rateLimited, ok := event.Data.(*slackevents.EventsAPIAppRateLimited)
+		if !ok {
+			return
+		}
+		callback(bot, newAppRateLimitedEvent(*rateLimited))
+	})
+}
+
+// appRateLimitedEvent parses an app_rate_limited body into an event which may be dispatched to event callbacks
+func appRateLimitedEvent(body []byte) (slackevents.EventsAPIEvent, error) {
+	var rateLimited slackevents.EventsAPIAppRateLimited
+	if err := json.Unmarshal(body, &rateLimited); err != nil {
+		return slackevents.EventsAPIEvent{}, err
+	}
+	return slackevents.EventsAPIEvent{
+		Token:    rateLimited.Token,
+		TeamID:   rateLimited.TeamID,
+		Type:     rateLimited.Type,
+		APIAppID: rateLimited.APIAppID,
+		Data:     &rateLimited,
+	}, nil
+}
+
+// applyBackpressure throttles the bot and alerts the ops channel in the background when OptionBackpressure is set
+func (b *Bot) applyBackpressure(ctx context.Context, event AppRateLimitedEvent) {
+	if b.metrics != nil {
+		b.metrics.appRateLimited.WithLabelValues(event.TeamID).Inc()
+	}
+	if b.backpressure == nil {
+		return
+	}
+
+	until := event.WindowEnd
+	if b.backpressure.PauseFor > 0 {
+		until = time.Now().Add(b.backpressure.PauseFor)
+	}
+	for {
+		current := atomic.LoadInt64(&b.throttledUntil)
+		if current >= until.UnixNano() || atomic.CompareAndSwapInt64(&b.throttledUntil, current, until.UnixNano()) {
+			break
+		}
+	}
+
+	if b.backpressure.AlertChannel == "" {
+		return
+	}
+	window := event.WindowStart.Unix()
+	last := atomic.LoadInt64(&b.lastAlertedWindow)
+	if last >= window || !atomic.CompareAndSwapInt64(&b.lastAlertedWindow, last, window) {
+		return
+	}
+	b.goDetached(ctx, func(ctx context.Context) {
+		b.alertAppRateLimited(ctx, event)
+	})
+}
+
+func (b *Bot) alertAppRateLimited(ctx context.Context, event AppRateLimitedEvent) {
+	text := fmt.Sprintf("Slack is rate limiting events for team %s: events between %s and %s were not delivered.",
+		event.TeamID, event.WindowStart.UTC().Format(time.RFC3339), event.WindowEnd.UTC().Format(time.RFC3339))
+	_, _, err := b.ApiForTeam(event.TeamID).PostMessageContext(ctx, b.backpressure.AlertChannel, slack.MsgOptionText(text, false))
+	if err != nil {
+		b.logger().Error("Could not post app rate limited alert", Fields{"channel": b.backpressure.AlertChannel, "error": err})
+	}
+}
+
+// Throttled reports whether non-critical async work should be paused because Slack is rate limiting the app.
+// Scheduled jobs and reminders are skipped while it is true. It is always false unless OptionBackpressure is set.
+func (b *Bot) Throttled() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&b.throttledUntil)
+}
+
+// WaitThrottle blocks until the bot is no longer throttled or ctx is done
+func (b *Bot) WaitThrottle(ctx context.Context) error {
+	for {
+		until := atomic.LoadInt64(&b.throttledUntil)
+		wait := time.Until(time.Unix(0, until))
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
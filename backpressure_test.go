@@ -0,0 +1,132 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newAppRateLimited(minute time.Time) slackevents.EventsAPIAppRateLimited {
+	return slackevents.EventsAPIAppRateLimited{
+		Type:              slackevents.AppRateLimited,
+		TeamID:            "T1",
+		APIAppID:          "A1",
+		MinuteRateLimited: int(minute.Unix()),
+	}
+}
+
+func TestRegisterAppRateLimited(t *testing.T) {
+	engine := gin.New()
+	bot := newBot()
+	bot.prepareEngine(engine, false)
+
+	minute := time.Now().Truncate(time.Minute)
+	var received AppRateLimitedEvent
+	bot.RegisterAppRateLimited(func(bot *Bot, event AppRateLimitedEvent) {
+		received = event
+	})
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newAppRateLimited(minute)).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.Equal(t, "T1", received.TeamID)
+	assert.Equal(t, "A1", received.APIAppID)
+	assert.Equal(t, minute.Unix(), received.WindowStart.Unix())
+	assert.Equal(t, minute.Add(time.Minute).Unix(), received.WindowEnd.Unix())
+}
+
+func TestUnregisterAppRateLimited(t *testing.T) {
+	engine := gin.New()
+	bot := newBot()
+	bot.prepareEngine(engine, false)
+
+	called := false
+	handle := bot.RegisterAppRateLimited(func(bot *Bot, event AppRateLimitedEvent) {
+		called = true
+	})
+	bot.UnregisterEvent(handle)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newAppRateLimited(time.Now())).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.False(t, called)
+}
+
+func TestThrottledWithoutBackpressure(t *testing.T) {
+	engine := gin.New()
+	bot := newBot()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newAppRateLimited(time.Now())).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.False(t, bot.Throttled())
+}
+
+func TestBackpressureThrottlesUntilEndOfWindow(t *testing.T) {
+	bot := NewBot("token", "secret", OptionBackpressure(BackpressureOptions{}))
+	minute := time.Now().Truncate(time.Minute)
+
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(minute)))
+	assert.True(t, bot.Throttled())
+	assert.Equal(t, minute.Add(time.Minute).UnixNano(), bot.throttledUntil)
+
+	// an older window never shortens the throttle
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(minute.Add(-time.Hour))))
+	assert.Equal(t, minute.Add(time.Minute).UnixNano(), bot.throttledUntil)
+}
+
+func TestBackpressurePauseFor(t *testing.T) {
+	bot := NewBot("token", "secret", OptionBackpressure(BackpressureOptions{PauseFor: 10 * time.Millisecond}))
+
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(time.Now())))
+	assert.True(t, bot.Throttled())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, bot.WaitThrottle(ctx))
+	assert.False(t, bot.Throttled())
+}
+
+func TestWaitThrottleCancelled(t *testing.T) {
+	bot := NewBot("token", "secret", OptionBackpressure(BackpressureOptions{PauseFor: time.Hour}))
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(time.Now())))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, bot.WaitThrottle(ctx))
+}
+
+func TestBackpressureAlertsOncePerWindow(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot := server.newBot(OptionBackpressure(BackpressureOptions{AlertChannel: "COPS"}), OptionTeamTokens(func(teamID string) string {
+		return "token-" + teamID
+	}))
+	minute := time.Now().Truncate(time.Minute)
+
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(minute)))
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(minute)))
+	bot.Shutdown(time.Second)
+
+	posted := server.callsTo("chat.postMessage")
+	if assert.Equal(t, 1, len(posted)) {
+		assert.Equal(t, "token-T1", posted[0].token)
+		assert.Equal(t, "COPS", posted[0].form.Get("channel"))
+		assert.Contains(t, posted[0].form.Get("text"), "T1")
+	}
+}
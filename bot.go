@@ -2,103 +2,321 @@ package slackbot
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
-	"github.com/toorop/gin-logrus"
+	"go.opentelemetry.io/otel/trace"
+	"net"
 	"net/http"
+	"os"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 //go:generate go run events.go
 
 type CommandCallback = func(bot *Bot, command slack.SlashCommand) *slack.Msg
-type eventCallback = func(bot *Bot, event slackevents.EventsAPIEvent)
+type CommandContextCallback = func(ctx context.Context, bot *Bot, command slack.SlashCommand) *slack.Msg
+type eventCallback = func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent)
+type RawEventCallback = func(bot *Bot, event json.RawMessage)
 type KeywordCallback = func(bot *Bot, container MessageEventContainer)
-type interactiveCallback = func(bot *Bot, interaction slack.InteractionCallback) (response interface{})
+type interactiveCallback = func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{})
+type InteractionContextCallback = interactiveCallback
 type SelectMenuOptionsCallback = func(bot *Bot, interaction slack.InteractionCallback) slack.OptionsResponse
 type SelectMenuOptionsGroupCallback = func(bot *Bot, interaction slack.InteractionCallback) slack.OptionGroupsResponse
 
+// Handle identifies a registered callback so that it may later be unregistered
+type Handle uint64
+
+type eventRegistration struct {
+	handle   Handle
+	priority int
+	callback eventCallback
+}
+
+type interactiveRegistration struct {
+	handle      Handle
+	priority    int
+	description string
+	// match reports whether the callback handles an interaction, nil matches every interaction of the type
+	match    func(interaction slack.InteractionCallback) bool
+	callback interactiveCallback
+}
+
 type Bot struct {
 	token         string
 	signingSecret string
+	apiURL        string
+	httpClient    *http.Client
+	slackOptions  []slack.Option
 
-	server *http.Server
-	log    *logrus.Logger
+	teamTokens  func(teamID string) string
+	clients     map[string]*slack.Client
+	clientsLock sync.Mutex
+
+	recorder Recorder
 
-	commands      map[string]CommandCallback
-	events        map[string][]eventCallback
-	interactives  map[slack.InteractionType][]interactiveCallback
-	selectOptions map[string]interface{}
+	metrics         *metrics
+	metricsPath     string
+	metricsGatherer prometheus.Gatherer
+
+	tracer  trace.Tracer
+	tracing bool
+
+	rateLimit          *RateLimitOptions
+	rateLimitTransport *rateLimitTransport
+
+	backpressure      *BackpressureOptions
+	throttledUntil    int64
+	lastAlertedWindow int64
+
+	healthEndpoints bool
+	debugToken      string
+	queues          map[string]func() int
+	started         time.Time
+	authenticated   int32
+	shuttingDown    int32
+
+	server *http.Server
+	log    Logger
+	// work started by callbacks which outlives their request, such as unfurls and reaction triggers
+	detached sync.WaitGroup
+
+	commands        map[string]interface{}
+	commandFallback interface{}
+	events          map[string][]eventRegistration
+	interactives    map[slack.InteractionType][]interactiveRegistration
+	selectOptions   map[string]interface{}
+	keywords        map[Handle]*regexp.Regexp
+	home            *home
+	unfurl          *unfurlers
+	scheduler       *scheduler
+	acl             *acl
+	lastHandle      Handle
 
 	sync.RWMutex
 }
 
 // Create a new Bot with given bot token and app signing secret
-func NewBot(token string, signingSecret string) *Bot {
-	return &Bot{
+func NewBot(token string, signingSecret string, options ...Option) *Bot {
+	b := &Bot{
 		token:         token,
 		signingSecret: signingSecret,
+		tracer:        trace.NewNoopTracerProvider().Tracer(tracerName),
+		started:       time.Now(),
 	}
+	for _, option := range options {
+		option(b)
+	}
+	if b.metrics != nil {
+		b.httpClient = b.metrics.instrumentClient(b.HTTPClient(), b.webAPIURL())
+	}
+	if b.rateLimit != nil {
+		b.httpClient = b.rateLimitClient(b.HTTPClient())
+	}
+	if b.tracing {
+		b.httpClient = traceClient(b.HTTPClient(), b.tracer, b.webAPIURL())
+	}
+	return b
 }
 
-// Get a slack.Client for interacting with the API
-func (b *Bot) Api() *slack.Client {
-	return slack.New(b.token)
-}
-
-func (b *Bot) logger() *logrus.Logger {
+func (b *Bot) logger() Logger {
 	if b.log == nil {
 		b.Lock()
 		defer b.Unlock()
 		if b.log == nil {
-			b.log = logrus.StandardLogger()
+			b.log = NewLogrusLogger(logrus.StandardLogger())
 		}
 	}
 	return b.log
 }
 
 // Set a pre-configured logrus.Logger to provide a consistent logging experience in your bot.
+// Use OptionLogger for other logging libraries.
 func (b *Bot) SetLogger(logger *logrus.Logger) {
 	b.Lock()
 	defer b.Unlock()
 
-	b.log = logger
+	b.log = NewLogrusLogger(logger)
 }
 
-// Register a slash command callback
+// Register a slash command callback. Commands may be registered before or after the bot is booted.
+// The name may be given with or without the leading slash.
 func (b *Bot) RegisterCommand(name string, callback CommandCallback) {
-	b.logger().Debugf("RegisterCommand %s", name)
+	b.logger().Debug("Registered command", Fields{"command": name})
+
+	b.registerCommand(name, callback)
+}
 
+// Register a slash command callback which receives the context of the request
+func (b *Bot) RegisterCommandContext(name string, callback CommandContextCallback) {
+	b.logger().Debug("Registered command", Fields{"command": name})
+
+	b.registerCommand(name, callback)
+}
+
+func (b *Bot) registerCommand(name string, callback interface{}) {
 	b.Lock()
 	defer b.Unlock()
 
 	if b.commands == nil {
-		b.commands = make(map[string]CommandCallback)
+		b.commands = make(map[string]interface{})
 	}
 
-	b.commands[name] = callback
+	b.commands[commandName(name)] = callback
 }
 
-func (b *Bot) registerEvent(eventType string, callback eventCallback) {
-	b.logger().Debugf("RegisterEvent %s", eventType)
+// Unregister a slash command callback by name
+func (b *Bot) UnregisterCommand(name string) {
+	b.logger().Debug("Unregistered command", Fields{"command": name})
+
+	b.Lock()
+	defer b.Unlock()
+
+	delete(b.commands, commandName(name))
+}
+
+// Register a callback for slash commands that have no registered callback.
+// Without a fallback, unknown commands are answered with 404 Not Found.
+func (b *Bot) RegisterCommandFallback(callback CommandCallback) {
+	b.registerCommandFallback(callback)
+}
+
+// Register a callback which receives the context of the request for slash commands that have no registered callback
+func (b *Bot) RegisterCommandFallbackContext(callback CommandContextCallback) {
+	b.registerCommandFallback(callback)
+}
+
+func (b *Bot) registerCommandFallback(callback interface{}) {
+	b.logger().Debug("Registered command fallback", nil)
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.commandFallback = callback
+}
+
+// command looks up the callback for a command name, falling back to the registered fallback if any
+func (b *Bot) command(name string) (interface{}, bool) {
+	b.RLock()
+	defer b.RUnlock()
+
+	callback, exists := b.commands[commandName(name)]
+	if !exists && b.commandFallback != nil {
+		return b.commandFallback, true
+	}
+	return callback, exists
+}
+
+func commandName(name string) string {
+	return strings.TrimPrefix(name, "/")
+}
+
+// nextHandle must be called with the write lock held
+func (b *Bot) nextHandle() Handle {
+	b.lastHandle++
+	return b.lastHandle
+}
+
+func (b *Bot) registerEvent(eventType string, callback eventCallback) Handle {
+	return b.registerEventGroup(map[string]eventCallback{eventType: callback})
+}
+
+// registerEventGroup registers callbacks for several event types under one Handle, so a feature spanning several
+// events is unregistered and prioritised as a whole
+func (b *Bot) registerEventGroup(group map[string]eventCallback) Handle {
+	for eventType := range group {
+		b.logger().Debug("Registered event", Fields{"event_type": eventType})
+	}
 
 	b.Lock()
 	defer b.Unlock()
 
 	if b.events == nil {
-		b.events = make(map[string][]eventCallback)
+		b.events = make(map[string][]eventRegistration)
+	}
+	handle := b.nextHandle()
+	for eventType, callback := range group {
+		b.events[eventType] = append(b.events[eventType], eventRegistration{handle: handle, callback: callback})
+	}
+	return handle
+}
+
+// Unregister an event callback using the Handle returned when it was registered
+func (b *Bot) UnregisterEvent(handle Handle) {
+	b.logger().Debug("Unregistered event", Fields{"handle": handle})
+
+	b.Lock()
+	defer b.Unlock()
+
+	delete(b.keywords, handle)
+	if b.home != nil && b.home.handle == handle {
+		b.home = nil
+	}
+	for eventType, registrations := range b.events {
+		for i, registration := range registrations {
+			if registration.handle == handle {
+				b.events[eventType] = append(registrations[:i:i], registrations[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Register a callback receiving the raw JSON of events of the given type, including types which have no generated container
+func (b *Bot) RegisterEvent(eventType string, callback RawEventCallback) Handle {
+	return b.registerEvent(eventType, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		raw, _ := ctx.Value(rawEventContextKey{}).(json.RawMessage)
+		callback(bot, raw)
+	})
+}
+
+// eventCallbacks returns a snapshot of the callbacks for event types so they may run without holding the lock.
+// Callbacks are ordered by priority, then by the order of eventTypes and registration.
+func (b *Bot) eventCallbacks(eventTypes ...string) []eventCallback {
+	b.RLock()
+	var registrations []eventRegistration
+	for _, eventType := range eventTypes {
+		registrations = append(registrations, b.events[eventType]...)
 	}
-	b.events[eventType] = append(b.events[eventType], callback)
+	b.RUnlock()
+
+	sort.SliceStable(registrations, func(i, j int) bool {
+		return registrations[i].priority > registrations[j].priority
+	})
+	callbacks := make([]eventCallback, len(registrations))
+	for i, registration := range registrations {
+		callbacks[i] = registration.callback
+	}
+	return callbacks
 }
 
 // Register a message event keyword regex callback.
-func (b *Bot) RegisterKeyword(regex *regexp.Regexp, callback KeywordCallback) {
-	b.logger().Debugf("RegisterKeyword %s", regex)
-	b.RegisterMessageEvent(b.newKeywordEventCallback(regex, callback))
+func (b *Bot) RegisterKeyword(regex *regexp.Regexp, callback KeywordCallback) Handle {
+	b.logger().Debug("Registered keyword", Fields{"keyword": regex.String()})
+	handle := b.RegisterMessageEvent(b.newKeywordEventCallback(regex, callback))
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.keywords == nil {
+		b.keywords = make(map[Handle]*regexp.Regexp)
+	}
+	b.keywords[handle] = regex
+
+	return handle
+}
+
+// Unregister a keyword callback using the Handle returned when it was registered
+func (b *Bot) UnregisterKeyword(handle Handle) {
+	b.UnregisterEvent(handle)
 }
 
 func (b *Bot) newKeywordEventCallback(regex *regexp.Regexp, callback KeywordCallback) MessageEventCallback {
@@ -109,31 +327,79 @@ func (b *Bot) newKeywordEventCallback(regex *regexp.Regexp, callback KeywordCall
 	}
 }
 
-func (b *Bot) registerInteractive(interactionType slack.InteractionType, callback interactiveCallback) {
-	b.logger().Debugf("RegisterInteractive %s", interactionType)
+func (b *Bot) registerInteractive(interactionType slack.InteractionType, callback interactiveCallback) Handle {
+	return b.registerDescribedInteractive(interactionType, "", nil, callback)
+}
+
+// registerDescribedInteractive registers an interaction callback which runs for interactions accepted by match,
+// with a description of what it matches for introspection
+func (b *Bot) registerDescribedInteractive(interactionType slack.InteractionType, description string, match func(interaction slack.InteractionCallback) bool, callback interactiveCallback) Handle {
+	return b.registerInteractiveGroup(map[slack.InteractionType]interactiveRegistration{
+		interactionType: {description: description, match: match, callback: callback},
+	})
+}
+
+// registerInteractiveGroup registers callbacks for several interaction types under one Handle, so a feature spanning
+// several interactions is unregistered and prioritised as a whole
+func (b *Bot) registerInteractiveGroup(group map[slack.InteractionType]interactiveRegistration) Handle {
+	for interactionType, registration := range group {
+		b.logger().Debug("Registered interaction", Fields{"interaction_type": interactionType, "description": registration.description})
+	}
 
 	b.Lock()
 	defer b.Unlock()
 
 	if b.interactives == nil {
-		b.interactives = make(map[slack.InteractionType][]interactiveCallback)
+		b.interactives = make(map[slack.InteractionType][]interactiveRegistration)
 	}
-	if _, exists := b.interactives[interactionType]; !exists {
-		b.interactives[interactionType] = make([]interactiveCallback, 0)
+	handle := b.nextHandle()
+	for interactionType, registration := range group {
+		registration.handle = handle
+		b.interactives[interactionType] = append(b.interactives[interactionType], registration)
 	}
-	b.interactives[interactionType] = append(b.interactives[interactionType], callback)
+	return handle
+}
+
+// Unregister an interaction callback using the Handle returned when it was registered
+func (b *Bot) UnregisterInteraction(handle Handle) {
+	b.logger().Debug("Unregistered interaction", Fields{"handle": handle})
+
+	b.Lock()
+	defer b.Unlock()
+
+	for interactionType, registrations := range b.interactives {
+		for i, registration := range registrations {
+			if registration.handle == handle {
+				b.interactives[interactionType] = append(registrations[:i:i], registrations[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// interactiveRegistrations returns a snapshot of the registrations for an interaction type, ordered by priority then
+// registration, so they may run without holding the lock
+func (b *Bot) interactiveRegistrations(interactionType slack.InteractionType) []interactiveRegistration {
+	b.RLock()
+	registrations := append([]interactiveRegistration(nil), b.interactives[interactionType]...)
+	b.RUnlock()
+
+	sort.SliceStable(registrations, func(i, j int) bool {
+		return registrations[i].priority > registrations[j].priority
+	})
+	return registrations
 }
 
 // Register a select options callback
 func (b *Bot) RegisterSelectOptions(callbackId string, callback SelectMenuOptionsCallback) {
-	b.logger().Debugf("RegisterSelectOptions %s", callbackId)
+	b.logger().Debug("Registered select options", Fields{"callback_id": callbackId})
 
 	b.registerSelectOptions(callbackId, callback)
 }
 
 // Register a select option groups callback
 func (b *Bot) RegisterSelectOptionGroups(callbackId string, callback SelectMenuOptionsGroupCallback) {
-	b.logger().Debugf("RegisterSelectOptions %s", callbackId)
+	b.logger().Debug("Registered select options", Fields{"callback_id": callbackId})
 
 	b.registerSelectOptions(callbackId, callback)
 }
@@ -159,7 +425,7 @@ func (b *Bot) Boot(listenAddr string) error {
 
 // Start the bot on the given listen address with a pre-configured instance of gin.Engine.
 func (b *Bot) BootWithEngine(listenAddr string, engine *gin.Engine) error {
-	b.logger().Infof("Booting slackbot on %s", listenAddr)
+	b.logger().Info("Booting slackbot", Fields{"listen_addr": listenAddr})
 
 	b.Lock()
 	defer b.Unlock()
@@ -167,28 +433,56 @@ func (b *Bot) BootWithEngine(listenAddr string, engine *gin.Engine) error {
 	if b.server != nil {
 		return ErrAlreadyBooted
 	}
+	atomic.StoreInt32(&b.shuttingDown, 0)
 
 	b.prepareEngine(engine, true)
 
-	b.server = &http.Server{
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
 		Addr:    listenAddr,
 		Handler: engine,
 	}
+	b.server = server
+	b.resumeSchedulerLocked()
 
 	go func() {
-		if err := b.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			b.logger().WithError(err).Fatalln("Failed to start server")
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			b.logger().Error("Failed to start server", Fields{"error": err})
+			os.Exit(1)
 		}
 	}()
 
 	return nil
 }
 
+// Get an http.Handler serving the bot's verified /slack routes without booting a server,
+// for mounting the bot in an existing server or exercising it in tests.
+func (b *Bot) Handler() http.Handler {
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	b.prepareEngine(engine, true)
+
+	return engine
+}
+
 func (b *Bot) prepareEngine(engine *gin.Engine, verify bool) {
-	engine.Use(ginlogrus.Logger(b.logger()))
+	engine.Use(b.newRequestLoggerMiddleware())
+	b.wireMetricsEndpoint(engine)
+	b.wireHealthEndpoints(engine)
 
 	slackGroup := engine.Group("/slack")
+	if b.metrics != nil {
+		slackGroup.Use(b.newMetricsMiddleware())
+	}
 	if verify {
+		// only live traffic is recorded, not replays
+		if b.recorder != nil {
+			slackGroup.Use(b.newRecorderMiddleware())
+		}
 		slackGroup.Use(b.newSlackVerifierMiddleware())
 	}
 
@@ -203,43 +497,47 @@ func (b *Bot) wireCallbacks(group *gin.RouterGroup) {
 }
 
 func (b *Bot) wireCommands(group *gin.RouterGroup) {
-	for name, callback := range b.commands {
-		b.logger().Infof("Wired command \"%s\" to %s/commands/%s", name, group.BasePath(), name)
-		group.POST("/commands/"+name, b.newCommandHandler(callback))
-	}
+	b.logger().Info("Wired commands", Fields{"path": group.BasePath() + "/commands"})
+	group.POST("/commands", b.newCommandHandler())
+	group.POST("/commands/:name", b.newCommandHandler())
 }
 
 func (b *Bot) wireEvents(group *gin.RouterGroup) {
-	b.logger().Infof("Wired events to %s/events", group.BasePath())
+	b.logger().Info("Wired events", Fields{"path": group.BasePath() + "/events"})
 	group.POST("/events", b.newEventHandler())
 }
 
 func (b *Bot) wireInteractives(group *gin.RouterGroup) {
-	b.logger().Infof("Wired interactives to %s/interactives", group.BasePath())
+	b.logger().Info("Wired interactives", Fields{"path": group.BasePath() + "/interactives"})
 	group.POST("/interactives", b.newInteractiveHandler())
 }
 
 func (b *Bot) wireSelectMenus(group *gin.RouterGroup) {
-	b.logger().Infof("Wired select menus to %s/menus", group.BasePath())
+	b.logger().Info("Wired select menus", Fields{"path": group.BasePath() + "/menus"})
 	group.POST("/menus", b.newSelectMenusHandler())
 }
 
-// Shutdown the bot gracefully with a given timeout
+// Shutdown the bot gracefully with a given timeout, cancelling scheduled jobs and waiting for running ones,
+// requests in flight and the work they started in the background
 func (b *Bot) Shutdown(timeout time.Duration) {
-	b.Lock()
-	defer b.Unlock()
-
-	if b.server == nil {
-		return
-	}
-
-	defer func() {
-		b.server = nil
-	}()
+	// the bot stays unready until it is booted again
+	atomic.StoreInt32(&b.shuttingDown, 1)
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	if err := b.server.Shutdown(ctx); err != nil {
-		b.logger().WithError(err).Fatalln("Server forced to shutdown")
+	b.stopScheduler(ctx)
+
+	// the lock is released before waiting for requests in flight, which may register callbacks
+	b.Lock()
+	server := b.server
+	b.server = nil
+	b.Unlock()
+
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			b.logger().Error("Server forced to shutdown", Fields{"error": err})
+			os.Exit(1)
+		}
 	}
+	b.waitDetached(ctx)
 }
@@ -1,12 +1,15 @@
 package slackbot
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
+	"net"
 	"net/http"
 	"reflect"
 	"regexp"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -36,19 +39,25 @@ func TestGetApi(t *testing.T) {
 	assert.NotEmpty(t, api)
 }
 
+func TestOptionAPIURL(t *testing.T) {
+	bot := NewBot("token", "secret", OptionAPIURL("http://localhost/api/"))
+
+	assert.Equal(t, "http://localhost/api/", bot.apiURL)
+}
+
 func TestSetLogger(t *testing.T) {
 	bot := newBot()
 
 	newLogger := logrus.New()
 	bot.SetLogger(newLogger)
 
-	assert.Equal(t, newLogger, bot.log)
+	assert.Same(t, newLogger, bot.log.(LogrusLogger).logger)
 }
 
 func TestGetLoggerReturnsStandardLoggerWhenUnset(t *testing.T) {
 	bot := newBot()
 
-	assert.Equal(t, logrus.StandardLogger(), bot.logger())
+	assert.Same(t, logrus.StandardLogger(), bot.logger().(LogrusLogger).logger)
 }
 
 func TestGetLoggerReturnsSetLogger(t *testing.T) {
@@ -57,7 +66,13 @@ func TestGetLoggerReturnsSetLogger(t *testing.T) {
 	newLogger := logrus.New()
 	bot.SetLogger(newLogger)
 
-	assert.Equal(t, newLogger, bot.logger())
+	assert.Same(t, newLogger, bot.logger().(LogrusLogger).logger)
+}
+
+func TestOptionLogger(t *testing.T) {
+	bot := NewBot("token", "secret", OptionLogger(NopLogger{}))
+
+	assert.Equal(t, NopLogger{}, bot.logger())
 }
 
 func TestRegisterCommand(t *testing.T) {
@@ -76,20 +91,49 @@ func TestRegisterCommand(t *testing.T) {
 	assert.Equal(t, reflect.ValueOf(test2Callback).Pointer(), reflect.ValueOf(bot.commands["command2"]).Pointer())
 }
 
+func TestUnregisterCommand(t *testing.T) {
+	bot := newBot()
+
+	bot.RegisterCommand("command1", func(b *Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+	bot.UnregisterCommand("command1")
+
+	_, exists := bot.command("command1")
+	assert.False(t, exists)
+}
+
 func TestRegisterEvent(t *testing.T) {
 	bot := newBot()
 
-	test1Callback := func(b *Bot, event slackevents.EventsAPIEvent) {}
+	test1Callback := func(ctx context.Context, b *Bot, event slackevents.EventsAPIEvent) {}
 	bot.registerEvent(slackevents.Message, test1Callback)
-	assert.Equal(t, reflect.ValueOf(test1Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.Message][0]).Pointer())
+	assert.Equal(t, reflect.ValueOf(test1Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.Message][0].callback).Pointer())
 
-	test2Callback := func(b *Bot, event slackevents.EventsAPIEvent) {}
+	test2Callback := func(ctx context.Context, b *Bot, event slackevents.EventsAPIEvent) {}
 	bot.registerEvent(slackevents.Message, test2Callback)
-	assert.Equal(t, reflect.ValueOf(test2Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.Message][1]).Pointer())
+	assert.Equal(t, reflect.ValueOf(test2Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.Message][1].callback).Pointer())
 
-	test3Callback := func(b *Bot, event slackevents.EventsAPIEvent) {}
+	test3Callback := func(ctx context.Context, b *Bot, event slackevents.EventsAPIEvent) {}
 	bot.registerEvent(slackevents.AppMention, test3Callback)
-	assert.Equal(t, reflect.ValueOf(test3Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.AppMention][0]).Pointer())
+	assert.Equal(t, reflect.ValueOf(test3Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.AppMention][0].callback).Pointer())
+}
+
+func TestUnregisterEvent(t *testing.T) {
+	bot := newBot()
+
+	test1Callback := func(ctx context.Context, b *Bot, event slackevents.EventsAPIEvent) {}
+	handle1 := bot.registerEvent(slackevents.Message, test1Callback)
+	test2Callback := func(ctx context.Context, b *Bot, event slackevents.EventsAPIEvent) {}
+	handle2 := bot.registerEvent(slackevents.Message, test2Callback)
+	assert.NotEqual(t, handle1, handle2)
+
+	bot.UnregisterEvent(handle1)
+	assert.Equal(t, 1, len(bot.events[slackevents.Message]))
+	assert.Equal(t, reflect.ValueOf(test2Callback).Pointer(), reflect.ValueOf(bot.events[slackevents.Message][0].callback).Pointer())
+
+	bot.UnregisterEvent(handle2)
+	assert.Equal(t, 0, len(bot.events[slackevents.Message]))
 }
 
 func TestRegisterKeyword(t *testing.T) {
@@ -101,6 +145,16 @@ func TestRegisterKeyword(t *testing.T) {
 	assert.Equal(t, 1, len(bot.events[slackevents.Message]))
 }
 
+func TestUnregisterKeyword(t *testing.T) {
+	bot := newBot()
+
+	keyword, _ := regexp.Compile("keyword")
+	handle := bot.RegisterKeyword(keyword, func(b *Bot, event MessageEventContainer) {})
+	bot.UnregisterKeyword(handle)
+
+	assert.Equal(t, 0, len(bot.events[slackevents.Message]))
+}
+
 func TestKeywordCallbackMatch(t *testing.T) {
 	keyword, _ := regexp.Compile("keyword")
 	text := "this text contains the keyword"
@@ -155,13 +209,34 @@ func newMessageEventContainer(text string) MessageEventContainer {
 func TestRegisterInteractive(t *testing.T) {
 	bot := newBot()
 
-	testCallback1 := func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) { return nil }
+	testCallback1 := func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return nil
+	}
 	bot.registerInteractive(slack.InteractionTypeBlockActions, testCallback1)
-	assert.Equal(t, reflect.ValueOf(testCallback1).Pointer(), reflect.ValueOf(bot.interactives[slack.InteractionTypeBlockActions][0]).Pointer())
+	assert.Equal(t, reflect.ValueOf(testCallback1).Pointer(), reflect.ValueOf(bot.interactives[slack.InteractionTypeBlockActions][0].callback).Pointer())
+
+	testCallback2 := func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return nil
+	}
+	bot.registerInteractive(slack.InteractionTypeBlockActions, testCallback2)
+	assert.Equal(t, reflect.ValueOf(testCallback2).Pointer(), reflect.ValueOf(bot.interactives[slack.InteractionTypeBlockActions][1].callback).Pointer())
+}
 
-	testCallback2 := func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) { return nil }
+func TestUnregisterInteraction(t *testing.T) {
+	bot := newBot()
+
+	testCallback1 := func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return nil
+	}
+	handle1 := bot.registerInteractive(slack.InteractionTypeBlockActions, testCallback1)
+	testCallback2 := func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return nil
+	}
 	bot.registerInteractive(slack.InteractionTypeBlockActions, testCallback2)
-	assert.Equal(t, reflect.ValueOf(testCallback2).Pointer(), reflect.ValueOf(bot.interactives[slack.InteractionTypeBlockActions][1]).Pointer())
+
+	bot.UnregisterInteraction(handle1)
+	assert.Equal(t, 1, len(bot.interactives[slack.InteractionTypeBlockActions]))
+	assert.Equal(t, reflect.ValueOf(testCallback2).Pointer(), reflect.ValueOf(bot.interactives[slack.InteractionTypeBlockActions][0].callback).Pointer())
 }
 
 func TestRegisterSelectOptions(t *testing.T) {
@@ -217,3 +292,59 @@ func TestBoot(t *testing.T) {
 
 	bot.Shutdown(time.Second * 10)
 }
+
+func TestShutdownWhileRegistering(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	bot := newBot()
+	started := make(chan struct{})
+	registered := make(chan struct{})
+	bot.RegisterCommand("slow", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		close(started)
+		for bot.Ready() {
+			time.Sleep(time.Millisecond)
+		}
+		// registering while the server waits for this request must not block shutdown
+		bot.RegisterCommand("late", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+			return nil
+		})
+		close(registered)
+		return &slack.Msg{Text: "done"}
+	})
+	atomic.StoreInt32(&bot.authenticated, 1)
+	assert.NoError(t, bot.Boot(addr))
+
+	responded := make(chan int, 1)
+	go func() {
+		request := newSignedRequest(t, "secret", "http://"+addr+"/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Fslow"))
+		resp, err := http.DefaultClient.Do(request)
+		if err != nil {
+			responded <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		responded <- resp.StatusCode
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("request did not reach the handler")
+	}
+
+	start := time.Now()
+	bot.Shutdown(5 * time.Second)
+	assert.True(t, time.Since(start) < time.Second)
+
+	select {
+	case <-registered:
+	default:
+		t.Fatal("handler did not register during shutdown")
+	}
+	assert.Equal(t, http.StatusOK, <-responded)
+}
@@ -0,0 +1,63 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Keys under which catch-all callbacks are registered; they can never collide with a Slack type
+const (
+	anyEventType             = "*"
+	unhandledEventType       = "*unhandled"
+	unhandledInteractionType = slack.InteractionType("*unhandled")
+)
+
+// Any Events API event, with its inner event parsed when it is modelled and its raw JSON
+type EventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Raw      json.RawMessage
+	ctx      context.Context
+}
+
+// Get the context of the request which delivered the event
+func (c EventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type EventCallback = func(bot *Bot, c EventContainer)
+
+func newEventContainerCallback(callback EventCallback) eventCallback {
+	return func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		raw, _ := ctx.Value(rawEventContextKey{}).(json.RawMessage)
+		callback(bot, EventContainer{APIEvent: event, Raw: raw, ctx: ctx})
+	}
+}
+
+// Register a callback for every event, before the callbacks registered for its type with the same priority
+func (b *Bot) RegisterAnyEvent(callback EventCallback) Handle {
+	return b.registerEvent(anyEventType, newEventContainerCallback(callback))
+}
+
+// Register a callback for events of a type which has no callbacks registered
+func (b *Bot) RegisterUnhandledEvent(callback EventCallback) Handle {
+	return b.registerEvent(unhandledEventType, newEventContainerCallback(callback))
+}
+
+// Register a callback for interactions which no registered callback matched, such as a block action with an unknown action ID.
+// Callback may return a response for Slack or nil for no response
+func (b *Bot) RegisterUnhandledInteraction(callback InteractionContextCallback) Handle {
+	return b.registerDescribedInteractive(unhandledInteractionType, "unhandled", nil, callback)
+}
+
+// dispatchedEventCallbacks returns the callbacks an event of eventType is dispatched to, including catch-all callbacks
+func (b *Bot) dispatchedEventCallbacks(eventType string) []eventCallback {
+	if len(b.eventCallbacks(eventType)) == 0 {
+		return b.eventCallbacks(anyEventType, unhandledEventType)
+	}
+	return b.eventCallbacks(anyEventType, eventType)
+}
@@ -0,0 +1,113 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestRegisterAnyEvent(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var order []string
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {
+		order = append(order, "app_mention")
+	})
+	bot.RegisterAnyEvent(func(bot *Bot, c EventContainer) {
+		order = append(order, "any:"+c.APIEvent.InnerEvent.Type)
+		assert.Contains(t, string(c.Raw), `"type":"app_mention"`)
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.AppMention)).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.Equal(t, []string{"any:app_mention", "app_mention"}, order)
+}
+
+func TestRegisterUnhandledEvent(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var unhandled []string
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {})
+	bot.RegisterUnhandledEvent(func(bot *Bot, c EventContainer) {
+		unhandled = append(unhandled, c.APIEvent.InnerEvent.Type)
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.AppMention)).
+		Expect().
+		Status(http.StatusOK)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.ReactionAdded)).
+		Expect().
+		Status(http.StatusOK)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: map[string]string{"type": "function_executed"}}).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Equal(t, []string{slackevents.ReactionAdded, "function_executed"}, unhandled)
+}
+
+func TestUnregisterUnhandledEvent(t *testing.T) {
+	bot := newBot()
+
+	handle := bot.RegisterUnhandledEvent(func(bot *Bot, c EventContainer) {})
+	bot.UnregisterEvent(handle)
+
+	assert.Equal(t, 0, len(bot.dispatchedEventCallbacks(slackevents.Message)))
+}
+
+func TestRegisterUnhandledInteraction(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	handled := 0
+	var unhandled []string
+	bot.RegisterShortcutInteraction("known", func(bot *Bot, interaction slack.InteractionCallback) {
+		handled++
+	})
+	bot.RegisterUnhandledInteraction(func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		unhandled = append(unhandled, interaction.CallbackID)
+		return nil
+	})
+	bot.prepareEngine(engine, false)
+
+	postInteraction(t, engine, slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "known"})
+	postInteraction(t, engine, slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "unknown"})
+	postInteraction(t, engine, slack.InteractionCallback{Type: slack.InteractionTypeMessageAction, CallbackID: "other"})
+
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, []string{"unknown", "other"}, unhandled)
+}
+
+func TestUnhandledInteractionResponse(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	bot.RegisterUnhandledInteraction(func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		return slack.NewErrorsViewSubmissionResponse(map[string]string{"block": "no longer supported"})
+	})
+	bot.prepareEngine(engine, false)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{Type: slack.InteractionTypeViewSubmission, View: slack.View{CallbackID: "old"}})
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("response_action", "errors")
+}
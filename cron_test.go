@@ -0,0 +1,65 @@
+package slackbot
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func mustParseCron(t *testing.T, expr string) *cronSchedule {
+	schedule, err := parseCron(expr)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return schedule
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "@sometimes"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Friday
+	from := time.Date(2021, 10, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2021, 10, 15, 9, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, 10, 15, 9, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2021, 10, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2021, 10, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2021, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2021, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 feb *", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		// a restricted day of month or day of week matches
+		{"0 0 1 * mon", time.Date(2021, 10, 18, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.next, mustParseCron(t, test.expr).next(from), test.expr)
+	}
+
+	assert.True(t, mustParseCron(t, "0 0 30 2 *").next(from).IsZero())
+}
+
+func TestCronNextDaylightSaving(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 2:30 does not exist on 2021-03-14, so the run is skipped
+	schedule := mustParseCron(t, "30 2 * * *")
+	assert.Equal(t, time.Date(2021, 3, 15, 2, 30, 0, 0, chicago), schedule.next(time.Date(2021, 3, 14, 0, 0, 0, 0, chicago)))
+
+	// 1:30 happens twice on 2021-11-07, and runs once
+	schedule = mustParseCron(t, "30 1 * * *")
+	first := schedule.next(time.Date(2021, 11, 7, 0, 0, 0, 0, chicago))
+	assert.Equal(t, 1, first.Hour())
+	assert.Equal(t, time.Date(2021, 11, 8, 1, 30, 0, 0, chicago), schedule.next(first))
+}
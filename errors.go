@@ -6,3 +6,8 @@ var ErrAlreadyBooted = errors.New("bot already booted")
 var ErrEmptyPayload = errors.New("empty payload")
 var ErrBadPayload = errors.New("bad payload")
 var ErrUnknownOptionsCallback = errors.New("unknown options callback")
+var ErrUnknownCommand = errors.New("unknown command")
+var ErrRateLimitDropped = errors.New("rate limited request dropped")
+var ErrNoHome = errors.New("no home registered")
+var ErrMessageNotFound = errors.New("message not found")
+var ErrNotFound = errors.New("not found")
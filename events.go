@@ -4,21 +4,110 @@ package main
 
 import (
 	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	"os"
 	"reflect"
+	"strings"
 	"text/template"
 	"time"
 )
 
-type EventTypes = map[string]string
+type EventType struct {
+	// Name used for the generated container, callback and Register function
+	Name string
+	// Package and struct which model the event, with an empty package for structs of slackbot itself
+	Package string
+	Struct  string
+}
+
+// Type returns the struct which models the event as referred to from slackbot
+func (e EventType) Type() string {
+	if e.Package == "" {
+		return e.Struct
+	}
+	return e.Package + "." + e.Struct
+}
+
+type EventTypes = map[string]EventType
+
+// Events API event types which slackevents does not model but which share their payload with an RTM event in slack.EventMapping
+var rtmEventTypes = []string{
+	"channel_archive",
+	"channel_created",
+	"channel_deleted",
+	"channel_history_changed",
+	"channel_left",
+	"channel_rename",
+	"channel_unarchive",
+	"dnd_updated",
+	"dnd_updated_user",
+	"email_domain_changed",
+	"emoji_changed",
+	"file_change",
+	"file_comment_added",
+	"file_comment_deleted",
+	"file_comment_edited",
+	"file_created",
+	"file_deleted",
+	"file_public",
+	"file_shared",
+	"file_unshared",
+	"group_archive",
+	"group_close",
+	"group_history_changed",
+	"group_left",
+	"group_open",
+	"group_unarchive",
+	"im_close",
+	"im_created",
+	"im_history_changed",
+	"im_open",
+	"star_added",
+	"star_removed",
+	"subteam_members_changed",
+	"subteam_self_added",
+	"subteam_self_removed",
+	"team_domain_change",
+	"team_join",
+	"team_rename",
+	"user_change",
+}
+
+// Event types whose struct in slack does not match what Slack sends, modelled by structs of slackbot instead
+var localEventTypes = map[string]string{
+	"group_rename":    "GroupRenameEvent",
+	"subteam_created": "SubteamCreatedEvent",
+	"subteam_updated": "SubteamUpdatedEvent",
+}
 
 func main() {
-	eventTypes := make(EventTypes, len(slackevents.EventsAPIInnerEventMapping))
+	eventTypes := make(EventTypes)
 	for name, event := range slackevents.EventsAPIInnerEventMapping {
-		reflection := reflect.ValueOf(event)
-		eventName := reflection.Type().Name()
-		eventTypes[name] = eventName
+		eventTypes[name] = newEventType(name, "slackevents", event)
+	}
+	for name, eventStruct := range localEventTypes {
+		eventTypes[name] = EventType{Name: eventStruct, Struct: eventStruct}
+	}
+	for _, name := range rtmEventTypes {
+		if _, exists := eventTypes[name]; exists {
+			continue
+		}
+		event, exists := slack.EventMapping[name]
+		if !exists {
+			logrus.WithField("event_type", name).Fatalln("Event type is not modelled by slack")
+		}
+		eventTypes[name] = newEventType(name, "slack", event)
+	}
+
+	// several event types may share a struct, in which case all but the one named after it are named after their type
+	for name, eventType := range eventTypes {
+		for otherName, other := range eventTypes {
+			if otherName != name && other.Struct == eventType.Struct && !strings.EqualFold(camelCase(name)+"Event", eventType.Struct) {
+				eventType.Name = camelCase(name) + "Event"
+				eventTypes[name] = eventType
+			}
+		}
 	}
 
 	f, err := os.Create("events_gen.go")
@@ -43,26 +132,63 @@ func main() {
 	}
 }
 
+func newEventType(name string, pkg string, event interface{}) EventType {
+	eventName := reflect.ValueOf(event).Type().Name()
+	return EventType{Name: eventName, Package: pkg, Struct: eventName}
+}
+
+func camelCase(name string) string {
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		words[i] = strings.Title(word)
+	}
+	return strings.Join(words, "")
+}
+
 var eventsTemplate = `package slackbot
 
 // Code generated by go generate; DO NOT EDIT.
 // This file was generated by robots at
 // {{ .Timestamp }}
 
-import "github.com/slack-go/slack/slackevents"
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Structs which model each Events API event type with a generated container
+var eventMapping = map[string]interface{}{
+{{- range $key, $event := .EventTypes }}
+	"{{ $key }}": {{ $event.Type }}{},
+{{- end }}
+}
 {{ range $key, $event := .EventTypes }}
-type {{ $event }}Container struct {
+type {{ $event.Name }}Container struct {
 	APIEvent slackevents.EventsAPIEvent
-	Event slackevents.{{ $event }}
+	Event {{ $event.Type }}
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c {{ $event.Name }}Container) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
-type {{ $event }}Callback = func(bot *Bot, c {{ $event }}Container)
+type {{ $event.Name }}Callback = func(bot *Bot, c {{ $event.Name }}Container)
 
 // Register a callback for {{ $key }} events
-func (b *Bot) Register{{ $event }}(callback {{ $event }}Callback) {
-	b.registerEvent("{{ $key }}", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.{{ $event }})
-		callback(b, {{ $event }}Container{APIEvent: event, Event: *e})
+func (b *Bot) Register{{ $event.Name }}(callback {{ $event.Name }}Callback) Handle {
+	return b.registerEvent("{{ $key }}", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*{{ $event.Type }})
+		if !ok {
+			return
+		}
+		callback(b, {{ $event.Name }}Container{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 {{ end }}
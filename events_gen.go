@@ -2,217 +2,1639 @@ package slackbot
 
 // Code generated by go generate; DO NOT EDIT.
 // This file was generated by robots at
-// 2020-11-12 01:20:46.374791 -0600 CST m=+0.000638937
+// 2026-10-18 22:36:29.477575986 +0000 UTC m=+0.001748950
 
-import "github.com/slack-go/slack/slackevents"
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Structs which model each Events API event type with a generated container
+var eventMapping = map[string]interface{}{
+	"app_home_opened": slackevents.AppHomeOpenedEvent{},
+	"app_mention": slackevents.AppMentionEvent{},
+	"app_uninstalled": slackevents.AppUninstalledEvent{},
+	"channel_archive": slack.ChannelArchiveEvent{},
+	"channel_created": slack.ChannelCreatedEvent{},
+	"channel_deleted": slack.ChannelDeletedEvent{},
+	"channel_history_changed": slack.ChannelHistoryChangedEvent{},
+	"channel_left": slack.ChannelLeftEvent{},
+	"channel_rename": slack.ChannelRenameEvent{},
+	"channel_unarchive": slack.ChannelUnarchiveEvent{},
+	"dnd_updated": slack.DNDUpdatedEvent{},
+	"dnd_updated_user": slack.DNDUpdatedEvent{},
+	"email_domain_changed": slack.EmailDomainChangedEvent{},
+	"emoji_changed": slack.EmojiChangedEvent{},
+	"file_change": slack.FileChangeEvent{},
+	"file_comment_added": slack.FileCommentAddedEvent{},
+	"file_comment_deleted": slack.FileCommentDeletedEvent{},
+	"file_comment_edited": slack.FileCommentEditedEvent{},
+	"file_created": slack.FileCreatedEvent{},
+	"file_deleted": slack.FileDeletedEvent{},
+	"file_public": slack.FilePublicEvent{},
+	"file_shared": slack.FileSharedEvent{},
+	"file_unshared": slack.FileUnsharedEvent{},
+	"grid_migration_finished": slackevents.GridMigrationFinishedEvent{},
+	"grid_migration_started": slackevents.GridMigrationStartedEvent{},
+	"group_archive": slack.GroupArchiveEvent{},
+	"group_close": slack.GroupCloseEvent{},
+	"group_history_changed": slack.GroupHistoryChangedEvent{},
+	"group_left": slack.GroupLeftEvent{},
+	"group_open": slack.GroupOpenEvent{},
+	"group_rename": GroupRenameEvent{},
+	"group_unarchive": slack.GroupUnarchiveEvent{},
+	"im_close": slack.IMCloseEvent{},
+	"im_created": slack.IMCreatedEvent{},
+	"im_history_changed": slack.IMHistoryChangedEvent{},
+	"im_open": slack.IMOpenEvent{},
+	"link_shared": slackevents.LinkSharedEvent{},
+	"member_joined_channel": slackevents.MemberJoinedChannelEvent{},
+	"member_left_channel": slackevents.MemberLeftChannelEvent{},
+	"message": slackevents.MessageEvent{},
+	"pin_added": slackevents.PinAddedEvent{},
+	"pin_removed": slackevents.PinRemovedEvent{},
+	"reaction_added": slackevents.ReactionAddedEvent{},
+	"reaction_removed": slackevents.ReactionRemovedEvent{},
+	"star_added": slack.StarAddedEvent{},
+	"star_removed": slack.StarRemovedEvent{},
+	"subteam_created": SubteamCreatedEvent{},
+	"subteam_members_changed": slack.SubteamMembersChangedEvent{},
+	"subteam_self_added": slack.SubteamSelfAddedEvent{},
+	"subteam_self_removed": slack.SubteamSelfRemovedEvent{},
+	"subteam_updated": SubteamUpdatedEvent{},
+	"team_domain_change": slack.TeamDomainChangeEvent{},
+	"team_join": slack.TeamJoinEvent{},
+	"team_rename": slack.TeamRenameEvent{},
+	"tokens_revoked": slackevents.TokensRevokedEvent{},
+	"user_change": slack.UserChangeEvent{},
+}
 
 type AppHomeOpenedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.AppHomeOpenedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c AppHomeOpenedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type AppHomeOpenedEventCallback = func(bot *Bot, c AppHomeOpenedEventContainer)
 
 // Register a callback for app_home_opened events
-func (b *Bot) RegisterAppHomeOpenedEvent(callback AppHomeOpenedEventCallback) {
-	b.registerEvent("app_home_opened", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.AppHomeOpenedEvent)
-		callback(b, AppHomeOpenedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterAppHomeOpenedEvent(callback AppHomeOpenedEventCallback) Handle {
+	return b.registerEvent("app_home_opened", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.AppHomeOpenedEvent)
+		if !ok {
+			return
+		}
+		callback(b, AppHomeOpenedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type AppMentionEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.AppMentionEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c AppMentionEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type AppMentionEventCallback = func(bot *Bot, c AppMentionEventContainer)
 
 // Register a callback for app_mention events
-func (b *Bot) RegisterAppMentionEvent(callback AppMentionEventCallback) {
-	b.registerEvent("app_mention", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.AppMentionEvent)
-		callback(b, AppMentionEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterAppMentionEvent(callback AppMentionEventCallback) Handle {
+	return b.registerEvent("app_mention", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.AppMentionEvent)
+		if !ok {
+			return
+		}
+		callback(b, AppMentionEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type AppUninstalledEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.AppUninstalledEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c AppUninstalledEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type AppUninstalledEventCallback = func(bot *Bot, c AppUninstalledEventContainer)
 
 // Register a callback for app_uninstalled events
-func (b *Bot) RegisterAppUninstalledEvent(callback AppUninstalledEventCallback) {
-	b.registerEvent("app_uninstalled", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.AppUninstalledEvent)
-		callback(b, AppUninstalledEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterAppUninstalledEvent(callback AppUninstalledEventCallback) Handle {
+	return b.registerEvent("app_uninstalled", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.AppUninstalledEvent)
+		if !ok {
+			return
+		}
+		callback(b, AppUninstalledEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelArchiveEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelArchiveEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelArchiveEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelArchiveEventCallback = func(bot *Bot, c ChannelArchiveEventContainer)
+
+// Register a callback for channel_archive events
+func (b *Bot) RegisterChannelArchiveEvent(callback ChannelArchiveEventCallback) Handle {
+	return b.registerEvent("channel_archive", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelArchiveEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelArchiveEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelCreatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelCreatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelCreatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelCreatedEventCallback = func(bot *Bot, c ChannelCreatedEventContainer)
+
+// Register a callback for channel_created events
+func (b *Bot) RegisterChannelCreatedEvent(callback ChannelCreatedEventCallback) Handle {
+	return b.registerEvent("channel_created", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelCreatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelCreatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelDeletedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelDeletedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelDeletedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelDeletedEventCallback = func(bot *Bot, c ChannelDeletedEventContainer)
+
+// Register a callback for channel_deleted events
+func (b *Bot) RegisterChannelDeletedEvent(callback ChannelDeletedEventCallback) Handle {
+	return b.registerEvent("channel_deleted", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelDeletedEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelDeletedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelHistoryChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelHistoryChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelHistoryChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelHistoryChangedEventCallback = func(bot *Bot, c ChannelHistoryChangedEventContainer)
+
+// Register a callback for channel_history_changed events
+func (b *Bot) RegisterChannelHistoryChangedEvent(callback ChannelHistoryChangedEventCallback) Handle {
+	return b.registerEvent("channel_history_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelHistoryChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelHistoryChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelLeftEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelLeftEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelLeftEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelLeftEventCallback = func(bot *Bot, c ChannelLeftEventContainer)
+
+// Register a callback for channel_left events
+func (b *Bot) RegisterChannelLeftEvent(callback ChannelLeftEventCallback) Handle {
+	return b.registerEvent("channel_left", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelLeftEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelLeftEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelRenameEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelRenameEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelRenameEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelRenameEventCallback = func(bot *Bot, c ChannelRenameEventContainer)
+
+// Register a callback for channel_rename events
+func (b *Bot) RegisterChannelRenameEvent(callback ChannelRenameEventCallback) Handle {
+	return b.registerEvent("channel_rename", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelRenameEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelRenameEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type ChannelUnarchiveEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.ChannelUnarchiveEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ChannelUnarchiveEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type ChannelUnarchiveEventCallback = func(bot *Bot, c ChannelUnarchiveEventContainer)
+
+// Register a callback for channel_unarchive events
+func (b *Bot) RegisterChannelUnarchiveEvent(callback ChannelUnarchiveEventCallback) Handle {
+	return b.registerEvent("channel_unarchive", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.ChannelUnarchiveEvent)
+		if !ok {
+			return
+		}
+		callback(b, ChannelUnarchiveEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type DNDUpdatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.DNDUpdatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c DNDUpdatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type DNDUpdatedEventCallback = func(bot *Bot, c DNDUpdatedEventContainer)
+
+// Register a callback for dnd_updated events
+func (b *Bot) RegisterDNDUpdatedEvent(callback DNDUpdatedEventCallback) Handle {
+	return b.registerEvent("dnd_updated", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.DNDUpdatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, DNDUpdatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type DndUpdatedUserEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.DNDUpdatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c DndUpdatedUserEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type DndUpdatedUserEventCallback = func(bot *Bot, c DndUpdatedUserEventContainer)
+
+// Register a callback for dnd_updated_user events
+func (b *Bot) RegisterDndUpdatedUserEvent(callback DndUpdatedUserEventCallback) Handle {
+	return b.registerEvent("dnd_updated_user", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.DNDUpdatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, DndUpdatedUserEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type EmailDomainChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.EmailDomainChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c EmailDomainChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type EmailDomainChangedEventCallback = func(bot *Bot, c EmailDomainChangedEventContainer)
+
+// Register a callback for email_domain_changed events
+func (b *Bot) RegisterEmailDomainChangedEvent(callback EmailDomainChangedEventCallback) Handle {
+	return b.registerEvent("email_domain_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.EmailDomainChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, EmailDomainChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type EmojiChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.EmojiChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c EmojiChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type EmojiChangedEventCallback = func(bot *Bot, c EmojiChangedEventContainer)
+
+// Register a callback for emoji_changed events
+func (b *Bot) RegisterEmojiChangedEvent(callback EmojiChangedEventCallback) Handle {
+	return b.registerEvent("emoji_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.EmojiChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, EmojiChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileChangeEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileChangeEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileChangeEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileChangeEventCallback = func(bot *Bot, c FileChangeEventContainer)
+
+// Register a callback for file_change events
+func (b *Bot) RegisterFileChangeEvent(callback FileChangeEventCallback) Handle {
+	return b.registerEvent("file_change", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileChangeEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileChangeEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileCommentAddedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileCommentAddedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileCommentAddedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileCommentAddedEventCallback = func(bot *Bot, c FileCommentAddedEventContainer)
+
+// Register a callback for file_comment_added events
+func (b *Bot) RegisterFileCommentAddedEvent(callback FileCommentAddedEventCallback) Handle {
+	return b.registerEvent("file_comment_added", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileCommentAddedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileCommentAddedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileCommentDeletedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileCommentDeletedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileCommentDeletedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileCommentDeletedEventCallback = func(bot *Bot, c FileCommentDeletedEventContainer)
+
+// Register a callback for file_comment_deleted events
+func (b *Bot) RegisterFileCommentDeletedEvent(callback FileCommentDeletedEventCallback) Handle {
+	return b.registerEvent("file_comment_deleted", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileCommentDeletedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileCommentDeletedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileCommentEditedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileCommentEditedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileCommentEditedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileCommentEditedEventCallback = func(bot *Bot, c FileCommentEditedEventContainer)
+
+// Register a callback for file_comment_edited events
+func (b *Bot) RegisterFileCommentEditedEvent(callback FileCommentEditedEventCallback) Handle {
+	return b.registerEvent("file_comment_edited", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileCommentEditedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileCommentEditedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileCreatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileCreatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileCreatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileCreatedEventCallback = func(bot *Bot, c FileCreatedEventContainer)
+
+// Register a callback for file_created events
+func (b *Bot) RegisterFileCreatedEvent(callback FileCreatedEventCallback) Handle {
+	return b.registerEvent("file_created", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileCreatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileCreatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileDeletedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileDeletedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileDeletedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileDeletedEventCallback = func(bot *Bot, c FileDeletedEventContainer)
+
+// Register a callback for file_deleted events
+func (b *Bot) RegisterFileDeletedEvent(callback FileDeletedEventCallback) Handle {
+	return b.registerEvent("file_deleted", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileDeletedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileDeletedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FilePublicEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FilePublicEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FilePublicEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FilePublicEventCallback = func(bot *Bot, c FilePublicEventContainer)
+
+// Register a callback for file_public events
+func (b *Bot) RegisterFilePublicEvent(callback FilePublicEventCallback) Handle {
+	return b.registerEvent("file_public", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FilePublicEvent)
+		if !ok {
+			return
+		}
+		callback(b, FilePublicEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileSharedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileSharedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileSharedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileSharedEventCallback = func(bot *Bot, c FileSharedEventContainer)
+
+// Register a callback for file_shared events
+func (b *Bot) RegisterFileSharedEvent(callback FileSharedEventCallback) Handle {
+	return b.registerEvent("file_shared", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileSharedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileSharedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type FileUnsharedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.FileUnsharedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c FileUnsharedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type FileUnsharedEventCallback = func(bot *Bot, c FileUnsharedEventContainer)
+
+// Register a callback for file_unshared events
+func (b *Bot) RegisterFileUnsharedEvent(callback FileUnsharedEventCallback) Handle {
+	return b.registerEvent("file_unshared", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.FileUnsharedEvent)
+		if !ok {
+			return
+		}
+		callback(b, FileUnsharedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type GridMigrationFinishedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.GridMigrationFinishedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GridMigrationFinishedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type GridMigrationFinishedEventCallback = func(bot *Bot, c GridMigrationFinishedEventContainer)
 
 // Register a callback for grid_migration_finished events
-func (b *Bot) RegisterGridMigrationFinishedEvent(callback GridMigrationFinishedEventCallback) {
-	b.registerEvent("grid_migration_finished", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.GridMigrationFinishedEvent)
-		callback(b, GridMigrationFinishedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterGridMigrationFinishedEvent(callback GridMigrationFinishedEventCallback) Handle {
+	return b.registerEvent("grid_migration_finished", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.GridMigrationFinishedEvent)
+		if !ok {
+			return
+		}
+		callback(b, GridMigrationFinishedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type GridMigrationStartedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.GridMigrationStartedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GridMigrationStartedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type GridMigrationStartedEventCallback = func(bot *Bot, c GridMigrationStartedEventContainer)
 
 // Register a callback for grid_migration_started events
-func (b *Bot) RegisterGridMigrationStartedEvent(callback GridMigrationStartedEventCallback) {
-	b.registerEvent("grid_migration_started", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.GridMigrationStartedEvent)
-		callback(b, GridMigrationStartedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterGridMigrationStartedEvent(callback GridMigrationStartedEventCallback) Handle {
+	return b.registerEvent("grid_migration_started", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.GridMigrationStartedEvent)
+		if !ok {
+			return
+		}
+		callback(b, GridMigrationStartedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupArchiveEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupArchiveEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupArchiveEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupArchiveEventCallback = func(bot *Bot, c GroupArchiveEventContainer)
+
+// Register a callback for group_archive events
+func (b *Bot) RegisterGroupArchiveEvent(callback GroupArchiveEventCallback) Handle {
+	return b.registerEvent("group_archive", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupArchiveEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupArchiveEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupCloseEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupCloseEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupCloseEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupCloseEventCallback = func(bot *Bot, c GroupCloseEventContainer)
+
+// Register a callback for group_close events
+func (b *Bot) RegisterGroupCloseEvent(callback GroupCloseEventCallback) Handle {
+	return b.registerEvent("group_close", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupCloseEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupCloseEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupHistoryChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupHistoryChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupHistoryChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupHistoryChangedEventCallback = func(bot *Bot, c GroupHistoryChangedEventContainer)
+
+// Register a callback for group_history_changed events
+func (b *Bot) RegisterGroupHistoryChangedEvent(callback GroupHistoryChangedEventCallback) Handle {
+	return b.registerEvent("group_history_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupHistoryChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupHistoryChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupLeftEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupLeftEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupLeftEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupLeftEventCallback = func(bot *Bot, c GroupLeftEventContainer)
+
+// Register a callback for group_left events
+func (b *Bot) RegisterGroupLeftEvent(callback GroupLeftEventCallback) Handle {
+	return b.registerEvent("group_left", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupLeftEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupLeftEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupOpenEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupOpenEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupOpenEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupOpenEventCallback = func(bot *Bot, c GroupOpenEventContainer)
+
+// Register a callback for group_open events
+func (b *Bot) RegisterGroupOpenEvent(callback GroupOpenEventCallback) Handle {
+	return b.registerEvent("group_open", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupOpenEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupOpenEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupRenameEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event GroupRenameEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupRenameEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupRenameEventCallback = func(bot *Bot, c GroupRenameEventContainer)
+
+// Register a callback for group_rename events
+func (b *Bot) RegisterGroupRenameEvent(callback GroupRenameEventCallback) Handle {
+	return b.registerEvent("group_rename", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*GroupRenameEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupRenameEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type GroupUnarchiveEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.GroupUnarchiveEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c GroupUnarchiveEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type GroupUnarchiveEventCallback = func(bot *Bot, c GroupUnarchiveEventContainer)
+
+// Register a callback for group_unarchive events
+func (b *Bot) RegisterGroupUnarchiveEvent(callback GroupUnarchiveEventCallback) Handle {
+	return b.registerEvent("group_unarchive", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.GroupUnarchiveEvent)
+		if !ok {
+			return
+		}
+		callback(b, GroupUnarchiveEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type IMCloseEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.IMCloseEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c IMCloseEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type IMCloseEventCallback = func(bot *Bot, c IMCloseEventContainer)
+
+// Register a callback for im_close events
+func (b *Bot) RegisterIMCloseEvent(callback IMCloseEventCallback) Handle {
+	return b.registerEvent("im_close", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.IMCloseEvent)
+		if !ok {
+			return
+		}
+		callback(b, IMCloseEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type IMCreatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.IMCreatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c IMCreatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type IMCreatedEventCallback = func(bot *Bot, c IMCreatedEventContainer)
+
+// Register a callback for im_created events
+func (b *Bot) RegisterIMCreatedEvent(callback IMCreatedEventCallback) Handle {
+	return b.registerEvent("im_created", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.IMCreatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, IMCreatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type IMHistoryChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.IMHistoryChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c IMHistoryChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type IMHistoryChangedEventCallback = func(bot *Bot, c IMHistoryChangedEventContainer)
+
+// Register a callback for im_history_changed events
+func (b *Bot) RegisterIMHistoryChangedEvent(callback IMHistoryChangedEventCallback) Handle {
+	return b.registerEvent("im_history_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.IMHistoryChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, IMHistoryChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type IMOpenEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.IMOpenEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c IMOpenEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type IMOpenEventCallback = func(bot *Bot, c IMOpenEventContainer)
+
+// Register a callback for im_open events
+func (b *Bot) RegisterIMOpenEvent(callback IMOpenEventCallback) Handle {
+	return b.registerEvent("im_open", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.IMOpenEvent)
+		if !ok {
+			return
+		}
+		callback(b, IMOpenEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type LinkSharedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.LinkSharedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c LinkSharedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type LinkSharedEventCallback = func(bot *Bot, c LinkSharedEventContainer)
 
 // Register a callback for link_shared events
-func (b *Bot) RegisterLinkSharedEvent(callback LinkSharedEventCallback) {
-	b.registerEvent("link_shared", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.LinkSharedEvent)
-		callback(b, LinkSharedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterLinkSharedEvent(callback LinkSharedEventCallback) Handle {
+	return b.registerEvent("link_shared", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.LinkSharedEvent)
+		if !ok {
+			return
+		}
+		callback(b, LinkSharedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type MemberJoinedChannelEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.MemberJoinedChannelEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c MemberJoinedChannelEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type MemberJoinedChannelEventCallback = func(bot *Bot, c MemberJoinedChannelEventContainer)
 
 // Register a callback for member_joined_channel events
-func (b *Bot) RegisterMemberJoinedChannelEvent(callback MemberJoinedChannelEventCallback) {
-	b.registerEvent("member_joined_channel", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.MemberJoinedChannelEvent)
-		callback(b, MemberJoinedChannelEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterMemberJoinedChannelEvent(callback MemberJoinedChannelEventCallback) Handle {
+	return b.registerEvent("member_joined_channel", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.MemberJoinedChannelEvent)
+		if !ok {
+			return
+		}
+		callback(b, MemberJoinedChannelEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type MemberLeftChannelEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.MemberLeftChannelEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c MemberLeftChannelEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type MemberLeftChannelEventCallback = func(bot *Bot, c MemberLeftChannelEventContainer)
 
 // Register a callback for member_left_channel events
-func (b *Bot) RegisterMemberLeftChannelEvent(callback MemberLeftChannelEventCallback) {
-	b.registerEvent("member_left_channel", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.MemberLeftChannelEvent)
-		callback(b, MemberLeftChannelEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterMemberLeftChannelEvent(callback MemberLeftChannelEventCallback) Handle {
+	return b.registerEvent("member_left_channel", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.MemberLeftChannelEvent)
+		if !ok {
+			return
+		}
+		callback(b, MemberLeftChannelEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type MessageEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.MessageEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c MessageEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type MessageEventCallback = func(bot *Bot, c MessageEventContainer)
 
 // Register a callback for message events
-func (b *Bot) RegisterMessageEvent(callback MessageEventCallback) {
-	b.registerEvent("message", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.MessageEvent)
-		callback(b, MessageEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterMessageEvent(callback MessageEventCallback) Handle {
+	return b.registerEvent("message", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.MessageEvent)
+		if !ok {
+			return
+		}
+		callback(b, MessageEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type PinAddedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.PinAddedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c PinAddedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type PinAddedEventCallback = func(bot *Bot, c PinAddedEventContainer)
 
 // Register a callback for pin_added events
-func (b *Bot) RegisterPinAddedEvent(callback PinAddedEventCallback) {
-	b.registerEvent("pin_added", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.PinAddedEvent)
-		callback(b, PinAddedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterPinAddedEvent(callback PinAddedEventCallback) Handle {
+	return b.registerEvent("pin_added", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.PinAddedEvent)
+		if !ok {
+			return
+		}
+		callback(b, PinAddedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type PinRemovedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.PinRemovedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c PinRemovedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type PinRemovedEventCallback = func(bot *Bot, c PinRemovedEventContainer)
 
 // Register a callback for pin_removed events
-func (b *Bot) RegisterPinRemovedEvent(callback PinRemovedEventCallback) {
-	b.registerEvent("pin_removed", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.PinRemovedEvent)
-		callback(b, PinRemovedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterPinRemovedEvent(callback PinRemovedEventCallback) Handle {
+	return b.registerEvent("pin_removed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.PinRemovedEvent)
+		if !ok {
+			return
+		}
+		callback(b, PinRemovedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type ReactionAddedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.ReactionAddedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ReactionAddedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type ReactionAddedEventCallback = func(bot *Bot, c ReactionAddedEventContainer)
 
 // Register a callback for reaction_added events
-func (b *Bot) RegisterReactionAddedEvent(callback ReactionAddedEventCallback) {
-	b.registerEvent("reaction_added", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.ReactionAddedEvent)
-		callback(b, ReactionAddedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterReactionAddedEvent(callback ReactionAddedEventCallback) Handle {
+	return b.registerEvent("reaction_added", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.ReactionAddedEvent)
+		if !ok {
+			return
+		}
+		callback(b, ReactionAddedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type ReactionRemovedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.ReactionRemovedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c ReactionRemovedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type ReactionRemovedEventCallback = func(bot *Bot, c ReactionRemovedEventContainer)
 
 // Register a callback for reaction_removed events
-func (b *Bot) RegisterReactionRemovedEvent(callback ReactionRemovedEventCallback) {
-	b.registerEvent("reaction_removed", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.ReactionRemovedEvent)
-		callback(b, ReactionRemovedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterReactionRemovedEvent(callback ReactionRemovedEventCallback) Handle {
+	return b.registerEvent("reaction_removed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.ReactionRemovedEvent)
+		if !ok {
+			return
+		}
+		callback(b, ReactionRemovedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type StarAddedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.StarAddedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c StarAddedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type StarAddedEventCallback = func(bot *Bot, c StarAddedEventContainer)
+
+// Register a callback for star_added events
+func (b *Bot) RegisterStarAddedEvent(callback StarAddedEventCallback) Handle {
+	return b.registerEvent("star_added", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.StarAddedEvent)
+		if !ok {
+			return
+		}
+		callback(b, StarAddedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type StarRemovedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.StarRemovedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c StarRemovedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type StarRemovedEventCallback = func(bot *Bot, c StarRemovedEventContainer)
+
+// Register a callback for star_removed events
+func (b *Bot) RegisterStarRemovedEvent(callback StarRemovedEventCallback) Handle {
+	return b.registerEvent("star_removed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.StarRemovedEvent)
+		if !ok {
+			return
+		}
+		callback(b, StarRemovedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type SubteamCreatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event SubteamCreatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c SubteamCreatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type SubteamCreatedEventCallback = func(bot *Bot, c SubteamCreatedEventContainer)
+
+// Register a callback for subteam_created events
+func (b *Bot) RegisterSubteamCreatedEvent(callback SubteamCreatedEventCallback) Handle {
+	return b.registerEvent("subteam_created", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*SubteamCreatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, SubteamCreatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type SubteamMembersChangedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.SubteamMembersChangedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c SubteamMembersChangedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type SubteamMembersChangedEventCallback = func(bot *Bot, c SubteamMembersChangedEventContainer)
+
+// Register a callback for subteam_members_changed events
+func (b *Bot) RegisterSubteamMembersChangedEvent(callback SubteamMembersChangedEventCallback) Handle {
+	return b.registerEvent("subteam_members_changed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.SubteamMembersChangedEvent)
+		if !ok {
+			return
+		}
+		callback(b, SubteamMembersChangedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type SubteamSelfAddedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.SubteamSelfAddedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c SubteamSelfAddedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type SubteamSelfAddedEventCallback = func(bot *Bot, c SubteamSelfAddedEventContainer)
+
+// Register a callback for subteam_self_added events
+func (b *Bot) RegisterSubteamSelfAddedEvent(callback SubteamSelfAddedEventCallback) Handle {
+	return b.registerEvent("subteam_self_added", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.SubteamSelfAddedEvent)
+		if !ok {
+			return
+		}
+		callback(b, SubteamSelfAddedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type SubteamSelfRemovedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.SubteamSelfRemovedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c SubteamSelfRemovedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type SubteamSelfRemovedEventCallback = func(bot *Bot, c SubteamSelfRemovedEventContainer)
+
+// Register a callback for subteam_self_removed events
+func (b *Bot) RegisterSubteamSelfRemovedEvent(callback SubteamSelfRemovedEventCallback) Handle {
+	return b.registerEvent("subteam_self_removed", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.SubteamSelfRemovedEvent)
+		if !ok {
+			return
+		}
+		callback(b, SubteamSelfRemovedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type SubteamUpdatedEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event SubteamUpdatedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c SubteamUpdatedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type SubteamUpdatedEventCallback = func(bot *Bot, c SubteamUpdatedEventContainer)
+
+// Register a callback for subteam_updated events
+func (b *Bot) RegisterSubteamUpdatedEvent(callback SubteamUpdatedEventCallback) Handle {
+	return b.registerEvent("subteam_updated", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*SubteamUpdatedEvent)
+		if !ok {
+			return
+		}
+		callback(b, SubteamUpdatedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type TeamDomainChangeEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.TeamDomainChangeEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c TeamDomainChangeEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type TeamDomainChangeEventCallback = func(bot *Bot, c TeamDomainChangeEventContainer)
+
+// Register a callback for team_domain_change events
+func (b *Bot) RegisterTeamDomainChangeEvent(callback TeamDomainChangeEventCallback) Handle {
+	return b.registerEvent("team_domain_change", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.TeamDomainChangeEvent)
+		if !ok {
+			return
+		}
+		callback(b, TeamDomainChangeEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type TeamJoinEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.TeamJoinEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c TeamJoinEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type TeamJoinEventCallback = func(bot *Bot, c TeamJoinEventContainer)
+
+// Register a callback for team_join events
+func (b *Bot) RegisterTeamJoinEvent(callback TeamJoinEventCallback) Handle {
+	return b.registerEvent("team_join", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.TeamJoinEvent)
+		if !ok {
+			return
+		}
+		callback(b, TeamJoinEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type TeamRenameEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.TeamRenameEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c TeamRenameEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type TeamRenameEventCallback = func(bot *Bot, c TeamRenameEventContainer)
+
+// Register a callback for team_rename events
+func (b *Bot) RegisterTeamRenameEvent(callback TeamRenameEventCallback) Handle {
+	return b.registerEvent("team_rename", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.TeamRenameEvent)
+		if !ok {
+			return
+		}
+		callback(b, TeamRenameEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
 type TokensRevokedEventContainer struct {
 	APIEvent slackevents.EventsAPIEvent
 	Event slackevents.TokensRevokedEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c TokensRevokedEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 type TokensRevokedEventCallback = func(bot *Bot, c TokensRevokedEventContainer)
 
 // Register a callback for tokens_revoked events
-func (b *Bot) RegisterTokensRevokedEvent(callback TokensRevokedEventCallback) {
-	b.registerEvent("tokens_revoked", func(bot *Bot, event slackevents.EventsAPIEvent) {
-		e := event.InnerEvent.Data.(*slackevents.TokensRevokedEvent)
-		callback(b, TokensRevokedEventContainer{APIEvent: event, Event: *e})
+func (b *Bot) RegisterTokensRevokedEvent(callback TokensRevokedEventCallback) Handle {
+	return b.registerEvent("tokens_revoked", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slackevents.TokensRevokedEvent)
+		if !ok {
+			return
+		}
+		callback(b, TokensRevokedEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
+	})
+}
+
+type UserChangeEventContainer struct {
+	APIEvent slackevents.EventsAPIEvent
+	Event slack.UserChangeEvent
+	ctx context.Context
+	bot *Bot
+}
+
+// Get the context of the request which delivered the event
+func (c UserChangeEventContainer) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+type UserChangeEventCallback = func(bot *Bot, c UserChangeEventContainer)
+
+// Register a callback for user_change events
+func (b *Bot) RegisterUserChangeEvent(callback UserChangeEventCallback) Handle {
+	return b.registerEvent("user_change", func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		e, ok := event.InnerEvent.Data.(*slack.UserChangeEvent)
+		if !ok {
+			return
+		}
+		callback(b, UserChangeEventContainer{APIEvent: event, Event: *e, ctx: ctx, bot: b})
 	})
 }
 
@@ -0,0 +1,125 @@
+package slackbot
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+// Inner events as Slack documents and sends them, for checking eventMapping against real payloads
+var eventPayloads = map[string]string{
+	"app_home_opened":         `{"type": "app_home_opened", "user": "U061F7AUR", "channel": "D0LAN2Q65", "event_ts": "1515449522000016", "tab": "home", "view": {"id": "VPASKP233", "team_id": "T21312902", "type": "home", "blocks": [], "private_metadata": "", "callback_id": "", "state": {"values": {}}, "hash": "1231232323.12321312", "clear_on_close": false, "notify_on_close": false, "root_view_id": "VPASKP233", "app_id": "A21SDS90", "external_id": "", "app_installed_team_id": "T21312902", "bot_id": "BSDKSAO2"}}`,
+	"app_mention":             `{"type": "app_mention", "user": "U061F7AUR", "text": "<@U0LAN0Z89> is it everything a river should be?", "ts": "1515449522.000016", "channel": "C0LAN2Q65", "event_ts": "1515449522000016"}`,
+	"app_uninstalled":         `{"type": "app_uninstalled"}`,
+	"channel_archive":         `{"type": "channel_archive", "channel": "C024BE91L", "user": "U024BE7LH"}`,
+	"channel_created":         `{"type": "channel_created", "channel": {"id": "C024BE91L", "name": "fun", "created": 1360782804, "creator": "U024BE7LH"}}`,
+	"channel_deleted":         `{"type": "channel_deleted", "channel": "C024BE91L"}`,
+	"channel_history_changed": `{"type": "channel_history_changed", "latest": "1358877455.000010", "ts": "1361482916.000003", "event_ts": "1361482916.000004"}`,
+	"channel_left":            `{"type": "channel_left", "channel": "C024BE91L"}`,
+	"channel_rename":          `{"type": "channel_rename", "channel": {"id": "C02ELGNBH", "name": "new_name", "created": 1360782804}}`,
+	"channel_unarchive":       `{"type": "channel_unarchive", "channel": "C024BE91L", "user": "U024BE7LH"}`,
+	"dnd_updated":             `{"type": "dnd_updated", "user": "U1234", "dnd_status": {"dnd_enabled": true, "next_dnd_start_ts": 1450387800, "next_dnd_end_ts": 1450423800, "snooze_enabled": true, "snooze_endtime": 1450373897}}`,
+	"dnd_updated_user":        `{"type": "dnd_updated_user", "user": "U1234", "dnd_status": {"dnd_enabled": true, "next_dnd_start_ts": 1450387800, "next_dnd_end_ts": 1450423800}}`,
+	"email_domain_changed":    `{"type": "email_domain_changed", "email_domain": "example.com", "event_ts": "1360782804.083113"}`,
+	"emoji_changed":           `{"type": "emoji_changed", "subtype": "add", "name": "picard_facepalm", "value": "https://my.slack.com/emoji/picard_facepalm/db8e287430eaa459.gif", "event_ts": "1361482916.000004"}`,
+	"file_change":             `{"type": "file_change", "file_id": "F2147483862", "file": {"id": "F2147483862"}}`,
+	"file_comment_deleted":    `{"type": "file_comment_deleted", "comment": "Fc67890", "file_id": "F2147483862", "file": {"id": "F2147483862"}}`,
+	"file_created":            `{"type": "file_created", "file_id": "F2147483862", "file": {"id": "F2147483862"}}`,
+	"file_deleted":            `{"type": "file_deleted", "file_id": "F2147483862", "event_ts": "1361482916.000004"}`,
+	"file_public":             `{"type": "file_public", "file_id": "F2147483862", "file": {"id": "F2147483862"}}`,
+	"file_shared":             `{"type": "file_shared", "channel_id": "C1234567890", "file_id": "F2147483862", "user_id": "U061F7AUR", "file": {"id": "F2147483862"}, "event_ts": "1361482916.000004"}`,
+	"file_unshared":           `{"type": "file_unshared", "file_id": "F2147483862", "file": {"id": "F2147483862"}}`,
+	"grid_migration_finished": `{"type": "grid_migration_finished", "enterprise_id": "EXXXXXXXX"}`,
+	"grid_migration_started":  `{"type": "grid_migration_started", "enterprise_id": "EXXXXXXXX"}`,
+	"group_archive":           `{"type": "group_archive", "channel": "G024BE91L"}`,
+	"group_close":             `{"type": "group_close", "user": "U024BE7LH", "channel": "G024BE91L"}`,
+	"group_history_changed":   `{"type": "group_history_changed", "latest": "1358877455.000010", "ts": "1361482916.000003", "event_ts": "1361482916.000004"}`,
+	"group_left":              `{"type": "group_left", "channel": "G02ELGNBH"}`,
+	"group_open":              `{"type": "group_open", "user": "U024BE7LH", "channel": "G024BE91L"}`,
+	"group_rename":            `{"type": "group_rename", "channel": {"id": "G02ELGNBH", "name": "new_name", "created": 1360782804}}`,
+	"group_unarchive":         `{"type": "group_unarchive", "channel": "G024BE91L"}`,
+	"im_close":                `{"type": "im_close", "user": "U024BE7LH", "channel": "D024BE91L"}`,
+	"im_created":              `{"type": "im_created", "user": "U024BE7LH", "channel": {"id": "D024BE91L"}}`,
+	"im_history_changed":      `{"type": "im_history_changed", "latest": "1358877455.000010", "ts": "1361482916.000003", "event_ts": "1361482916.000004"}`,
+	"im_open":                 `{"type": "im_open", "user": "U024BE7LH", "channel": "D024BE91L"}`,
+	"link_shared":             `{"type": "link_shared", "channel": "Cxxxxxx", "user": "Uxxxxxxx", "message_ts": "123456789.9875", "thread_ts": "123456621.1855", "links": [{"domain": "example.com", "url": "https://example.com/12345"}, {"domain": "another-example.com", "url": "https://yet.another-example.com/v/abcde"}]}`,
+	"member_joined_channel":   `{"type": "member_joined_channel", "user": "W06GH7XHN", "channel": "C0698JE0H", "channel_type": "C", "team": "T024BE7LD", "inviter": "U123456789"}`,
+	"member_left_channel":     `{"type": "member_left_channel", "user": "W06GH7XHN", "channel": "C0698JE0H", "channel_type": "C", "team": "T024BE7LD"}`,
+	"message":                 `{"type": "message", "channel": "C2147483705", "user": "U2147483697", "text": "Hello world", "ts": "1355517523.000005", "event_ts": "1355517523.000005", "channel_type": "channel", "client_msg_id": "5ba2a4b4-7a7c-4e6e-8e3a-9b1b0d1a2c3d", "team": "T024BE7LD", "blocks": [{"type": "rich_text", "block_id": "ZxG", "elements": [{"type": "rich_text_section", "elements": [{"type": "text", "text": "Hello world"}]}]}]}`,
+	"pin_added":               `{"type": "pin_added", "user": "U024BE7LH", "channel_id": "C02ELGNBH", "item": {"type": "message", "channel": "C02ELGNBH", "message": {"type": "message", "user": "U024BE7LH", "text": "pinned", "ts": "1360782804.083113"}}, "event_ts": "1360782804.083113"}`,
+	"pin_removed":             `{"type": "pin_removed", "user": "U024BE7LH", "channel_id": "C02ELGNBH", "item": {"type": "message", "channel": "C02ELGNBH", "message": {"type": "message", "user": "U024BE7LH", "text": "pinned", "ts": "1360782804.083113"}}, "has_pins": false, "event_ts": "1360782804.083113"}`,
+	"reaction_added":          `{"type": "reaction_added", "user": "U024BE7LH", "reaction": "thumbsup", "item_user": "U0G9QF9C6", "item": {"type": "message", "channel": "C0G9QF9GZ", "ts": "1360782400.498405"}, "event_ts": "1360782804.083113"}`,
+	"reaction_removed":        `{"type": "reaction_removed", "user": "U024BE7LH", "reaction": "thumbsup", "item_user": "U0G9QF9C6", "item": {"type": "message", "channel": "C0G9QF9GZ", "ts": "1360782400.498405"}, "event_ts": "1360782804.083113"}`,
+	"star_added":              `{"type": "star_added", "user": "U024BE7LH", "item": {"type": "message", "channel": "C02ELGNBH", "message": {"type": "message", "user": "U024BE7LH", "text": "starred", "ts": "1360782804.083113"}}, "event_ts": "1360782804.083113"}`,
+	"star_removed":            `{"type": "star_removed", "user": "U024BE7LH", "item": {"type": "message", "channel": "C02ELGNBH", "message": {"type": "message", "user": "U024BE7LH", "text": "starred", "ts": "1360782804.083113"}}, "event_ts": "1360782804.083113"}`,
+	"subteam_created":         `{"type": "subteam_created", "subteam": {"id": "S0615G0KT", "team_id": "T060RNRCH", "is_usergroup": true, "name": "Marketing Team", "handle": "marketing-team", "date_create": 1446746793, "date_update": 1446746793, "date_delete": 0, "created_by": "U060RNRCZ", "updated_by": "U060RNRCZ", "prefs": {"channels": [], "groups": []}, "user_count": "0"}}`,
+	"subteam_members_changed": `{"type": "subteam_members_changed", "subteam_id": "S0614TZR7", "team_id": "T060RNRCH", "date_previous_update": 1446670362, "date_update": 1492906952, "added_users": ["U060RNRCZ", "U060ULRC0", "U061309JM"], "added_users_count": "3", "removed_users": ["U06129G2V"], "removed_users_count": "1"}`,
+	"subteam_self_added":      `{"type": "subteam_self_added", "subteam_id": "S0615G0KT"}`,
+	"subteam_self_removed":    `{"type": "subteam_self_removed", "subteam_id": "S0615G0KT"}`,
+	"subteam_updated":         `{"type": "subteam_updated", "subteam": {"id": "S0614TZR7", "team_id": "T060RNRCH", "is_usergroup": true, "name": "Team Admins", "handle": "admins", "date_create": 1446670362, "date_update": 1446670362, "date_delete": 0, "created_by": "USLACKBOT", "updated_by": "U060RNRCZ", "prefs": {"channels": [], "groups": []}, "users": ["U060RNRCZ", "U060ULRC0"], "user_count": "2"}}`,
+	"team_domain_change":      `{"type": "team_domain_change", "url": "https://my.slack.com", "domain": "my"}`,
+	"team_join":               `{"type": "team_join", "user": {"id": "W012A3CDE", "team_id": "T012AB3C4", "name": "spengler", "deleted": false, "real_name": "Egon Spengler", "tz": "America/Los_Angeles", "tz_label": "Pacific Daylight Time", "tz_offset": -25200, "profile": {"real_name": "Egon Spengler", "display_name": "spengler", "email": "spengler@ghostbusters.example.com", "image_24": "https://.../photo.jpg", "team": "T012AB3C4"}, "is_admin": true, "is_owner": false, "is_primary_owner": false, "is_restricted": false, "is_ultra_restricted": false, "is_bot": false, "updated": 1502138686, "is_app_user": false, "has_2fa": false}}`,
+	"team_rename":             `{"type": "team_rename", "name": "New Team Name Inc."}`,
+	"tokens_revoked":          `{"type": "tokens_revoked", "tokens": {"oauth": ["UXXXXXXXX"], "bot": ["UXXXXXXXX"]}}`,
+	"user_change":             `{"type": "user_change", "user": {"id": "W012A3CDE", "team_id": "T012AB3C4", "name": "spengler", "deleted": false, "real_name": "Egon Spengler", "tz": "America/Los_Angeles", "tz_offset": -25200, "profile": {"real_name": "Egon Spengler", "display_name": "spengler", "status_text": "Print is dead", "status_emoji": ":books:", "status_expiration": 0, "team": "T012AB3C4"}, "is_admin": true, "is_bot": false, "updated": 1502138686}, "cache_ts": 1502138686, "event_ts": "1502138686.000100"}`,
+}
+
+// Payloads which do not match the struct of their type, as when Slack changes what it sends, which are passed on as
+// json.RawMessage
+var mismatchedEventPayloads = map[string]string{
+	"channel_rename": `{"type": "channel_rename", "channel": {"id": "C02ELGNBH", "name": "new_name", "created": "1360782804"}}`,
+}
+
+func callbackEventBody(t *testing.T, inner string) []byte {
+	body, err := json.Marshal(map[string]interface{}{
+		"token":      "token",
+		"team_id":    "T060RNRCH",
+		"api_app_id": "A0MDYCDME",
+		"type":       "event_callback",
+		"event_id":   "Ev0MDYHUEL",
+		"event_time": 1515449522,
+		"event":      json.RawMessage(inner),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestEventMappingParsesPayloads(t *testing.T) {
+	bot := newBot()
+
+	for eventType, payload := range eventPayloads {
+		event, raw, err := bot.parseEvent(callbackEventBody(t, payload))
+		if !assert.NoError(t, err, eventType) {
+			continue
+		}
+		assert.Equal(t, eventType, event.InnerEvent.Type)
+		assert.JSONEq(t, payload, string(raw), eventType)
+		assert.IsType(t, reflect.New(reflect.TypeOf(eventMapping[eventType])).Interface(), event.InnerEvent.Data, eventType)
+	}
+
+	for eventType, payload := range mismatchedEventPayloads {
+		event, raw, err := bot.parseEvent(callbackEventBody(t, payload))
+		if !assert.NoError(t, err, eventType) {
+			continue
+		}
+		assert.Equal(t, eventType, event.InnerEvent.Type)
+		assert.JSONEq(t, payload, string(raw), eventType)
+		assert.IsType(t, json.RawMessage{}, event.InnerEvent.Data, eventType)
+	}
+}
+
+func TestEventPayloadsCoverEventMapping(t *testing.T) {
+	// file comment events are no longer sent, so only file_comment_deleted is covered
+	skipped := []string{"file_comment_added", "file_comment_edited"}
+
+	for eventType := range eventMapping {
+		_, parsed := eventPayloads[eventType]
+		_, mismatched := mismatchedEventPayloads[eventType]
+		if !contains(skipped, eventType) {
+			assert.True(t, parsed || mismatched, eventType)
+		}
+	}
+}
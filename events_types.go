@@ -0,0 +1,35 @@
+package slackbot
+
+import (
+	"encoding/json"
+	"github.com/slack-go/slack"
+)
+
+// Events API event types whose struct in slack does not decode what Slack sends. They are listed in localEventTypes of
+// events.go, which generates their containers and Register functions.
+
+// Sent when a private channel is renamed. slack.GroupRenameEvent models the creation time as a string.
+type GroupRenameEvent struct {
+	Type      string                  `json:"type"`
+	Group     slack.ChannelRenameInfo `json:"channel"`
+	Timestamp string                  `json:"ts"`
+}
+
+// A user group as sent in subteam events, which send its user count as a string rather than the number
+// slack.UserGroup models. The user count of the embedded slack.UserGroup is always zero.
+type SubteamEventUserGroup struct {
+	slack.UserGroup
+	UserCount json.Number `json:"user_count"`
+}
+
+// Sent when a user group is created
+type SubteamCreatedEvent struct {
+	Type    string                `json:"type"`
+	Subteam SubteamEventUserGroup `json:"subteam"`
+}
+
+// Sent when a user group is updated, or its members change
+type SubteamUpdatedEvent struct {
+	Type    string                `json:"type"`
+	Subteam SubteamEventUserGroup `json:"subteam"`
+}
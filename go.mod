@@ -5,8 +5,12 @@ go 1.14
 require (
 	github.com/gavv/httpexpect/v2 v2.1.0
 	github.com/gin-gonic/gin v1.6.3
+	github.com/prometheus/client_golang v1.11.0
 	github.com/sirupsen/logrus v1.7.0
 	github.com/slack-go/slack v0.7.2
-	github.com/stretchr/testify v1.4.0
-	github.com/toorop/gin-logrus v0.0.0-20200831135515-d2ee50d38dae
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	go.uber.org/zap v1.19.1
 )
@@ -1,6 +1,7 @@
 package slackbot
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -9,9 +10,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"time"
 )
 
-func (b *Bot) newCommandHandler(callback CommandCallback) gin.HandlerFunc {
+// newCommandHandler dispatches on the :name path parameter when present, otherwise on the command form field
+func (b *Bot) newCommandHandler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		command, err := slack.SlashCommandParse(ctx.Request)
 		if err != nil {
@@ -23,7 +26,37 @@ func (b *Bot) newCommandHandler(callback CommandCallback) gin.HandlerFunc {
 			return
 		}
 
-		msg := callback(b, command)
+		name := ctx.Param("name")
+		if name == "" {
+			name = command.Command
+		}
+
+		callback, exists := b.command(name)
+		if !exists {
+			_ = ctx.AbortWithError(http.StatusNotFound, ErrUnknownCommand)
+			return
+		}
+
+		fields := addLogFields(ctx, commandFields(command))
+		b.logger().Debug("Dispatching command", fields)
+
+		spanCtx, span := b.startSpan(ctx, "slackbot.command", fields)
+		defer span.End()
+
+		if denied := b.authorizeCommand(spanCtx, name, command); denied != nil {
+			ctx.JSON(http.StatusOK, denied)
+			return
+		}
+
+		start := time.Now()
+		var msg *slack.Msg
+		switch cb := callback.(type) {
+		case CommandCallback:
+			msg = cb(b, command)
+		case CommandContextCallback:
+			msg = cb(spanCtx, b, command)
+		}
+		b.observeDispatch(dispatchKindCommand, commandName(name), "", start)
 
 		if msg != nil {
 			ctx.JSON(http.StatusOK, msg)
@@ -41,7 +74,7 @@ func (b *Bot) newEventHandler() gin.HandlerFunc {
 			return
 		}
 
-		event, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken()) // verification handled by middleware
+		event, raw, err := b.parseEvent(body)
 		if err != nil {
 			_ = ctx.AbortWithError(http.StatusBadRequest, err)
 			return
@@ -59,28 +92,106 @@ func (b *Bot) newEventHandler() gin.HandlerFunc {
 		}
 
 		if event.Type == slackevents.CallbackEvent {
-			innerEvent := event.InnerEvent
-			b.RLock()
-			defer b.RUnlock()
-			for eventType, eventCallbacks := range b.events {
-				if innerEvent.Type == eventType {
-					for _, callback := range eventCallbacks {
-						callback(b, event)
-					}
+			fields := addLogFields(ctx, eventFields(event, body))
+			b.logger().Debug("Dispatching event", fields)
+
+			spanCtx, span := b.startSpan(ctx, "slackbot.event", fields)
+			defer span.End()
+			spanCtx = context.WithValue(spanCtx, rawEventContextKey{}, raw)
+
+			spanCtx = withPropagation(spanCtx)
+
+			start := time.Now()
+			for _, callback := range b.dispatchedEventCallbacks(event.InnerEvent.Type) {
+				callback(spanCtx, b, event)
+				if propagationStopped(spanCtx) {
+					break
 				}
 			}
+			b.observeDispatch(dispatchKindEvent, event.InnerEvent.Type, "", start)
 			ctx.Status(http.StatusOK)
 			return
 		}
 
 		if event.Type == slackevents.AppRateLimited {
-			_ = ctx.Error(errors.New("app rate limited"))
+			event, err := appRateLimitedEvent(body)
+			if err != nil {
+				_ = ctx.AbortWithError(http.StatusBadRequest, err)
+				return
+			}
+			rateLimited := newAppRateLimitedEvent(*event.Data.(*slackevents.EventsAPIAppRateLimited))
+
+			fields := addLogFields(ctx, Fields{"team_id": rateLimited.TeamID, "minute_rate_limited": rateLimited.WindowStart.Unix()})
+			b.logger().Warn("App rate limited, Slack is dropping events", fields)
+
+			spanCtx, span := b.startSpan(ctx, "slackbot.event", fields)
+			defer span.End()
+			b.applyBackpressure(spanCtx, rateLimited)
+			spanCtx = context.WithValue(spanCtx, rawEventContextKey{}, json.RawMessage(body))
+
+			spanCtx = withPropagation(spanCtx)
+
+			start := time.Now()
+			for _, callback := range b.eventCallbacks(slackevents.AppRateLimited) {
+				callback(spanCtx, b, event)
+				if propagationStopped(spanCtx) {
+					break
+				}
+			}
+			b.observeDispatch(dispatchKindEvent, slackevents.AppRateLimited, "", start)
 			ctx.Status(http.StatusOK)
 			return
 		}
 	}
 }
 
+type rawEventContextKey struct{}
+
+// parseEvent parses an Events API body like slackevents.ParseEvent, but models every type in eventMapping and
+// leaves other callback events as json.RawMessage rather than failing. The raw inner event of callback events is also returned.
+// Events which do not match their modeled struct, such as a number Slack sends as a string, are also left as
+// json.RawMessage, so they only reach RegisterEvent callbacks.
+func (b *Bot) parseEvent(body []byte) (slackevents.EventsAPIEvent, json.RawMessage, error) {
+	var outer slackevents.EventsAPICallbackEvent
+	if err := json.Unmarshal(body, &outer); err != nil {
+		return slackevents.EventsAPIEvent{}, nil, err
+	}
+	if outer.Type != slackevents.CallbackEvent {
+		event, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken()) // verification handled by middleware
+		return event, nil, err
+	}
+	if outer.InnerEvent == nil {
+		return slackevents.EventsAPIEvent{}, nil, ErrBadPayload
+	}
+
+	raw := *outer.InnerEvent
+	var inner struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &inner); err != nil {
+		return slackevents.EventsAPIEvent{}, nil, err
+	}
+
+	var data interface{} = raw
+	if model, exists := eventMapping[inner.Type]; exists {
+		modeled := reflect.New(reflect.TypeOf(model)).Interface()
+		if err := json.Unmarshal(raw, modeled); err != nil {
+			b.logger().Warn("Failed to parse event, passing it on as raw JSON", Fields{"team_id": outer.TeamID, "event_type": inner.Type, "error": err})
+		} else {
+			data = modeled
+		}
+	}
+
+	return slackevents.EventsAPIEvent{
+		Token:      outer.Token,
+		TeamID:     outer.TeamID,
+		Type:       outer.Type,
+		APIAppID:   outer.APIAppID,
+		Data:       &outer,
+		InnerEvent: slackevents.EventsAPIInnerEvent{Type: inner.Type, Data: data},
+	}, raw, nil
+}
+
 func (b *Bot) newInteractiveHandler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		payload := ctx.PostForm("payload")
@@ -96,20 +207,58 @@ func (b *Bot) newInteractiveHandler() gin.HandlerFunc {
 			return
 		}
 
-		b.RLock()
-		defer b.RUnlock()
-		callbacks, typeExists := b.interactives[interactionCallback.Type]
-		if typeExists {
-			for _, callback := range callbacks {
-				response := callback(b, interactionCallback)
-				isNilPtr := reflect.ValueOf(response).Kind() == reflect.Ptr && reflect.ValueOf(response).IsNil()
-				if response != nil && !isNilPtr {
-					ctx.JSON(http.StatusOK, response)
-					return
-				}
+		fields := addLogFields(ctx, interactionFields(interactionCallback))
+		b.logger().Debug("Dispatching interaction", fields)
+
+		spanCtx, span := b.startSpan(ctx, "slackbot.interaction", fields)
+		defer span.End()
+		defer b.observeDispatch(dispatchKindInteraction, string(interactionCallback.Type), interactionCallbackID(interactionCallback), time.Now())
+
+		if !b.authorizeInteraction(spanCtx, interactionCallback, []byte(payload)) {
+			ctx.Status(http.StatusOK)
+			return
+		}
+
+		registrations := b.interactiveRegistrations(interactionCallback.Type)
+		handled := false
+		for _, registration := range registrations {
+			if registration.match == nil || registration.match(interactionCallback) {
+				handled = true
+				break
+			}
+		}
+		if !handled {
+			b.logger().Debug("Unhandled interaction", fields)
+			registrations = b.interactiveRegistrations(unhandledInteractionType)
+		}
+
+		spanCtx = withPropagation(spanCtx)
+		var responses []interface{}
+		for _, registration := range registrations {
+			if registration.match != nil && !registration.match(interactionCallback) {
+				continue
+			}
+			response := registration.callback(spanCtx, b, interactionCallback)
+			if response == Handled {
+				break
+			}
+			isNilPtr := reflect.ValueOf(response).Kind() == reflect.Ptr && reflect.ValueOf(response).IsNil()
+			if response != nil && !isNilPtr {
+				responses = append(responses, response)
+			}
+			if propagationStopped(spanCtx) {
+				break
 			}
 		}
 
+		response, discarded := composeInteractionResponses(responses)
+		if discarded > 0 {
+			b.logger().Warn("Discarded interaction responses", addLogFields(ctx, Fields{"discarded": discarded}))
+		}
+		if response != nil {
+			ctx.JSON(http.StatusOK, response)
+			return
+		}
 		ctx.Status(http.StatusOK)
 	}
 }
@@ -134,6 +283,13 @@ func (b *Bot) newSelectMenusHandler() gin.HandlerFunc {
 			return
 		}
 
+		fields := addLogFields(ctx, interactionFields(interactionCallback))
+		b.logger().Debug("Dispatching select menu", fields)
+
+		_, span := b.startSpan(ctx, "slackbot.select_menu", fields)
+		defer span.End()
+		defer b.observeDispatch(dispatchKindSelectMenu, string(interactionCallback.Type), interactionCallback.CallbackID, time.Now())
+
 		b.RLock()
 		defer b.RUnlock()
 		callback, exists := b.selectOptions[interactionCallback.CallbackID]
@@ -153,3 +309,11 @@ func (b *Bot) newSelectMenusHandler() gin.HandlerFunc {
 		_ = ctx.AbortWithError(http.StatusInternalServerError, ErrUnknownOptionsCallback)
 	}
 }
+
+// interactionCallbackID returns the callback ID of an interaction, which is found on the view for modal interactions
+func interactionCallbackID(interaction slack.InteractionCallback) string {
+	if interaction.CallbackID != "" {
+		return interaction.CallbackID
+	}
+	return interaction.View.CallbackID
+}
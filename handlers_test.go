@@ -1,6 +1,7 @@
 package slackbot
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/slack-go/slack"
@@ -60,11 +61,126 @@ func TestCommandHandlerWithInvalidRequest(t *testing.T) {
 		Status(http.StatusBadRequest)
 }
 
+func TestCommandHandlerWithUnknownCommand(t *testing.T) {
+	bot := newBot()
+
+	engine := gin.New()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/commands/test").
+		WithFormField("command", "/test").
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestCommandHandlerRegisteredAfterPrepare(t *testing.T) {
+	bot := newBot()
+
+	engine := gin.New()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/commands/test").
+		WithFormField("command", "/test").
+		Expect().
+		Status(http.StatusNotFound)
+
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "registered late"}
+	})
+	e.POST("/slack/commands/test").
+		WithFormField("command", "/test").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "registered late")
+
+	bot.UnregisterCommand("test")
+	e.POST("/slack/commands/test").
+		WithFormField("command", "/test").
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestEventHandlerUnregisteredCallback(t *testing.T) {
+	hitCallbackOne := false
+
+	engine := gin.New()
+
+	bot := newBot()
+	handle := bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+		hitCallbackOne = true
+	})
+	bot.prepareEngine(engine, false)
+	bot.UnregisterEvent(handle)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.AppMention)).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.False(t, hitCallbackOne)
+}
+
+func TestCommandHandlerSingleEndpoint(t *testing.T) {
+	bot := newBot()
+	bot.RegisterCommand("test1", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "test1"}
+	})
+	bot.RegisterCommand("/test2", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "test2"}
+	})
+
+	engine := gin.New()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/commands").
+		WithFormField("command", "/test1").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "test1")
+	e.POST("/slack/commands").
+		WithFormField("command", "/test2").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "test2")
+	e.POST("/slack/commands/test2").
+		WithFormField("command", "/test2").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "test2")
+	e.POST("/slack/commands").
+		WithFormField("command", "/test3").
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestCommandHandlerFallback(t *testing.T) {
+	bot := newBot()
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "test"}
+	})
+	bot.RegisterCommandFallback(func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: "unknown " + command.Command}
+	})
+
+	engine := gin.New()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/commands").
+		WithFormField("command", "/test").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "test")
+	e.POST("/slack/commands").
+		WithFormField("command", "/other").
+		Expect().
+		Status(http.StatusOK).JSON().Object().ValueEqual("text", "unknown /other")
+}
+
 func TestEventHandlerErrorsWithNoContent(t *testing.T) {
 	engine := gin.New()
 
 	bot := newBot()
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 
 	})
 	bot.prepareEngine(engine, false)
@@ -81,7 +197,7 @@ func TestEventHandlerUrlVerification(t *testing.T) {
 	engine := gin.New()
 
 	bot := newBot()
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 
 	})
 	bot.prepareEngine(engine, false)
@@ -103,7 +219,7 @@ func TestEventHandlerSingleCallback(t *testing.T) {
 	engine := gin.New()
 
 	bot := newBot()
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 		hitCallbackOne = true
 	})
 	bot.prepareEngine(engine, false)
@@ -124,10 +240,10 @@ func TestEventHandlerHitsAllCallbacksOfTheSameType(t *testing.T) {
 	engine := gin.New()
 
 	bot := newBot()
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 		hitCallbackOne = true
 	})
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 		hitCallbackTwo = true
 	})
 	bot.prepareEngine(engine, false)
@@ -149,10 +265,10 @@ func TestEventHandlerHitsCorrectCallback(t *testing.T) {
 	engine := gin.New()
 
 	bot := newBot()
-	bot.registerEvent(slackevents.AppMention, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.AppMention, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 		hitCallbackOne = true
 	})
-	bot.registerEvent(slackevents.Message, func(bot *Bot, event slackevents.EventsAPIEvent) {
+	bot.registerEvent(slackevents.Message, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
 		hitCallbackTwo = true
 	})
 	bot.prepareEngine(engine, false)
@@ -180,6 +296,173 @@ func TestEventHandlerRateLimited(t *testing.T) {
 		Status(http.StatusOK).NoContent()
 }
 
+func TestEventHandlerTypedContainerForEventOutsideSlackevents(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var received ChannelCreatedEventContainer
+	bot.RegisterChannelCreatedEvent(func(bot *Bot, c ChannelCreatedEventContainer) {
+		received = c
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{
+			Type: slackevents.CallbackEvent,
+			Event: slack.ChannelCreatedEvent{
+				Type:    "channel_created",
+				Channel: slack.ChannelCreatedInfo{ID: "C1", Name: "general"},
+			},
+		}).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.Equal(t, "C1", received.Event.Channel.ID)
+	assert.Equal(t, "general", received.Event.Channel.Name)
+	assert.Equal(t, "channel_created", received.APIEvent.InnerEvent.Type)
+}
+
+func TestEventHandlerSharedStructEventTypes(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	hitUpdated := false
+	hitUpdatedUser := false
+	bot.RegisterDNDUpdatedEvent(func(bot *Bot, c DNDUpdatedEventContainer) {
+		hitUpdated = true
+	})
+	bot.RegisterDndUpdatedUserEvent(func(bot *Bot, c DndUpdatedUserEventContainer) {
+		hitUpdatedUser = true
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: slack.DNDUpdatedEvent{Type: "dnd_updated_user", User: "U1"}}).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.False(t, hitUpdated)
+	assert.True(t, hitUpdatedUser)
+}
+
+func TestEventHandlerRawEventForUnmodelledType(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var received json.RawMessage
+	bot.RegisterEvent("workflow_step_execute", func(bot *Bot, event json.RawMessage) {
+		received = event
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{
+			Type:  slackevents.CallbackEvent,
+			Event: map[string]string{"type": "workflow_step_execute", "callback_id": "step"},
+		}).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	var event map[string]string
+	assert.NoError(t, json.Unmarshal(received, &event))
+	assert.Equal(t, "step", event["callback_id"])
+}
+
+func TestEventHandlerRawEventForModelledType(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var raw json.RawMessage
+	var typed AppMentionEventContainer
+	bot.RegisterEvent(slackevents.AppMention, func(bot *Bot, event json.RawMessage) {
+		raw = event
+	})
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {
+		typed = c
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEventWithData(slackevents.AppMention, slackevents.AppMentionEvent{Text: "hello"})).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.Contains(t, string(raw), `"text":"hello"`)
+	assert.Equal(t, "hello", typed.Event.Text)
+}
+
+func TestEventHandlerUnmodelledTypeWithoutCallbacks(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: map[string]string{"type": "function_executed"}}).
+		Expect().
+		Status(http.StatusOK).NoContent()
+}
+
+func TestEventHandlerSubteamEvents(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var created SubteamCreatedEventContainer
+	var updated SubteamUpdatedEventContainer
+	bot.RegisterSubteamCreatedEvent(func(bot *Bot, c SubteamCreatedEventContainer) {
+		created = c
+	})
+	bot.RegisterSubteamUpdatedEvent(func(bot *Bot, c SubteamUpdatedEventContainer) {
+		updated = c
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	for _, eventType := range []string{"subteam_created", "subteam_updated"} {
+		e.POST("/slack/events").
+			WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: json.RawMessage(eventPayloads[eventType])}).
+			Expect().
+			Status(http.StatusOK).NoContent()
+	}
+
+	// Slack sends user_count as a string
+	assert.Equal(t, "S0615G0KT", created.Event.Subteam.ID)
+	assert.Equal(t, json.Number("0"), created.Event.Subteam.UserCount)
+	assert.Equal(t, "admins", updated.Event.Subteam.Handle)
+	assert.Equal(t, json.Number("2"), updated.Event.Subteam.UserCount)
+	assert.Equal(t, []string{"U060RNRCZ", "U060ULRC0"}, updated.Event.Subteam.Users)
+}
+
+func TestEventHandlerMismatchedModelledType(t *testing.T) {
+	payload := mismatchedEventPayloads["channel_rename"]
+	engine := gin.New()
+
+	bot := newBot()
+	var raw json.RawMessage
+	typed := false
+	bot.RegisterEvent("channel_rename", func(bot *Bot, event json.RawMessage) {
+		raw = event
+	})
+	bot.RegisterChannelRenameEvent(func(bot *Bot, c ChannelRenameEventContainer) {
+		typed = true
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: json.RawMessage(payload)}).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.JSONEq(t, payload, string(raw))
+	assert.False(t, typed)
+}
+
 func newFakeEvent(eventType string) fakeEvent {
 	return newFakeEventWithData(eventType, nil)
 }
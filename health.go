@@ -0,0 +1,135 @@
+package slackbot
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Introspection of a bot's registered callbacks and runtime state, served by the debug endpoint
+type DebugInfo struct {
+	Uptime          string              `json:"uptime"`
+	Ready           bool                `json:"ready"`
+	Commands        []string            `json:"commands"`
+	CommandFallback bool                `json:"command_fallback"`
+	Events          map[string]int      `json:"events"`
+	Keywords        []string            `json:"keywords"`
+	Interactions    map[string][]string `json:"interactions"`
+	SelectMenus     []string            `json:"select_menus"`
+	Queues          map[string]int      `json:"queues"`
+}
+
+// registerQueue exposes the depth of an internal queue on the debug endpoint
+func (b *Bot) registerQueue(name string, depth func() int) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.queues == nil {
+		b.queues = make(map[string]func() int)
+	}
+	b.queues[name] = depth
+}
+
+// Check whether the bot is ready to serve traffic: its token has passed auth.test and it has not been shut down.
+// auth.test is called until it first succeeds.
+func (b *Bot) Ready() bool {
+	if atomic.LoadInt32(&b.shuttingDown) == 1 {
+		return false
+	}
+	if atomic.LoadInt32(&b.authenticated) == 1 {
+		return true
+	}
+
+	if _, err := b.Api().AuthTest(); err != nil {
+		b.logger().Warn("Readiness auth.test failed", Fields{"error": err})
+		return false
+	}
+	atomic.StoreInt32(&b.authenticated, 1)
+	return true
+}
+
+// Get a snapshot of the bot's registered callbacks and runtime state
+func (b *Bot) DebugInfo() DebugInfo {
+	b.RLock()
+	defer b.RUnlock()
+
+	info := DebugInfo{
+		Uptime:          time.Since(b.started).Round(time.Second).String(),
+		Ready:           atomic.LoadInt32(&b.shuttingDown) == 0 && atomic.LoadInt32(&b.authenticated) == 1,
+		Commands:        make([]string, 0, len(b.commands)),
+		CommandFallback: b.commandFallback != nil,
+		Events:          make(map[string]int),
+		Keywords:        make([]string, 0, len(b.keywords)),
+		Interactions:    make(map[string][]string),
+		SelectMenus:     make([]string, 0, len(b.selectOptions)),
+		Queues:          make(map[string]int),
+	}
+
+	for name := range b.commands {
+		info.Commands = append(info.Commands, name)
+	}
+	for eventType, registrations := range b.events {
+		if len(registrations) > 0 {
+			info.Events[eventType] = len(registrations)
+		}
+	}
+	for _, keyword := range b.keywords {
+		info.Keywords = append(info.Keywords, keyword.String())
+	}
+	for interactionType, registrations := range b.interactives {
+		for _, registration := range registrations {
+			info.Interactions[string(interactionType)] = append(info.Interactions[string(interactionType)], registration.description)
+		}
+	}
+	for callbackID := range b.selectOptions {
+		info.SelectMenus = append(info.SelectMenus, callbackID)
+	}
+	for name, depth := range b.queues {
+		info.Queues[name] = depth()
+	}
+
+	sort.Strings(info.Commands)
+	sort.Strings(info.Keywords)
+	sort.Strings(info.SelectMenus)
+
+	return info
+}
+
+func (b *Bot) wireHealthEndpoints(engine *gin.Engine) {
+	if b.healthEndpoints {
+		b.logger().Info("Wired health checks", Fields{"path": "/healthz"})
+		engine.GET("/healthz", func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, "ok")
+		})
+
+		b.logger().Info("Wired readiness checks", Fields{"path": "/readyz"})
+		engine.GET("/readyz", func(ctx *gin.Context) {
+			if !b.Ready() {
+				ctx.String(http.StatusServiceUnavailable, "not ready")
+				return
+			}
+			ctx.String(http.StatusOK, "ok")
+		})
+	}
+
+	if b.debugToken != "" {
+		b.logger().Info("Wired debug endpoint", Fields{"path": "/debug/slackbot"})
+		engine.GET("/debug/slackbot", b.newDebugTokenMiddleware(), func(ctx *gin.Context) {
+			ctx.JSON(http.StatusOK, b.DebugInfo())
+		})
+	}
+}
+
+func (b *Bot) newDebugTokenMiddleware() gin.HandlerFunc {
+	expected := []byte("Bearer " + b.debugToken)
+	return func(ctx *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), expected) != 1 {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		ctx.Next()
+	}
+}
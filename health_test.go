@@ -0,0 +1,118 @@
+package slackbot
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newAuthTestSlack answers auth.test successfully while ok is set
+func newAuthTestSlack(ok *int32) *fakeSlack {
+	server := newFakeSlack()
+	server.setResponseFunc("auth.test", func(call apiCall) interface{} {
+		if atomic.LoadInt32(ok) == 1 {
+			return map[string]interface{}{"ok": true}
+		}
+		return map[string]interface{}{"ok": false, "error": "invalid_auth"}
+	})
+	return server
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	var ok int32
+	server := newAuthTestSlack(&ok)
+	defer server.Close()
+
+	bot := server.newBot(OptionHealthEndpoints())
+	e := getHttpExpect(t, bot.Handler().(*gin.Engine))
+
+	e.GET("/healthz").Expect().Status(http.StatusOK)
+	e.GET("/readyz").Expect().Status(http.StatusServiceUnavailable)
+
+	atomic.StoreInt32(&ok, 1)
+	e.GET("/readyz").Expect().Status(http.StatusOK)
+
+	// auth.test is not repeated once it has succeeded
+	atomic.StoreInt32(&ok, 0)
+	e.GET("/readyz").Expect().Status(http.StatusOK)
+
+	atomic.StoreInt32(&bot.shuttingDown, 1)
+	e.GET("/readyz").Expect().Status(http.StatusServiceUnavailable)
+}
+
+func TestHealthEndpointsDisabledByDefault(t *testing.T) {
+	e := getHttpExpect(t, newBot().Handler().(*gin.Engine))
+
+	e.GET("/healthz").Expect().Status(http.StatusNotFound)
+	e.GET("/debug/slackbot").Expect().Status(http.StatusNotFound)
+}
+
+func TestDebugEndpoint(t *testing.T) {
+	bot := NewBot("token", "secret", OptionDebugEndpoint("debug-token"))
+	bot.RegisterCommand("command1", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+	bot.RegisterKeyword(regexp.MustCompile("fire"), func(bot *Bot, c MessageEventContainer) {})
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {})
+	bot.RegisterShortcutInteraction("shortcut1", func(bot *Bot, interaction slack.InteractionCallback) {})
+	bot.RegisterBlockActionsInteraction(BlockActionFilter{ActionID: "action1"}, func(bot *Bot, interaction slack.InteractionCallback) {})
+	bot.RegisterSelectOptions("menu1", func(bot *Bot, interaction slack.InteractionCallback) slack.OptionsResponse {
+		return slack.OptionsResponse{}
+	})
+	bot.registerQueue("outbound", func() int { return 3 })
+
+	e := getHttpExpect(t, bot.Handler().(*gin.Engine))
+	e.GET("/debug/slackbot").Expect().Status(http.StatusUnauthorized)
+	e.GET("/debug/slackbot").WithHeader("Authorization", "Bearer wrong").Expect().Status(http.StatusUnauthorized)
+
+	info := e.GET("/debug/slackbot").
+		WithHeader("Authorization", "Bearer debug-token").
+		Expect().
+		Status(http.StatusOK).JSON().Object()
+
+	info.Value("commands").Array().Equal([]string{"command1"})
+	info.Value("events").Object().ValueEqual("message", 1).ValueEqual("app_mention", 1)
+	info.Value("keywords").Array().Equal([]string{"fire"})
+	info.Value("interactions").Object().ValueEqual("shortcut", []string{"callback_id=shortcut1"})
+	info.Value("interactions").Object().ValueEqual("block_actions", []string{"action_id=action1 block_id="})
+	info.Value("select_menus").Array().Equal([]string{"menu1"})
+	info.Value("queues").Object().ValueEqual("outbound", 3)
+	info.Value("uptime").String().NotEmpty()
+}
+
+func TestDebugInfoForgetsUnregisteredKeywords(t *testing.T) {
+	bot := newBot()
+	handle := bot.RegisterKeyword(regexp.MustCompile("fire"), func(bot *Bot, c MessageEventContainer) {})
+	bot.UnregisterKeyword(handle)
+
+	info := bot.DebugInfo()
+
+	assert.Empty(t, info.Keywords)
+	assert.Empty(t, info.Events)
+}
+
+func TestNotReadyAfterShutdown(t *testing.T) {
+	bot := NewBot("token", "secret", OptionHealthEndpoints())
+	atomic.StoreInt32(&bot.authenticated, 1)
+	engine := gin.New()
+	assert.NoError(t, bot.BootWithEngine("localhost:0", engine))
+	e := getHttpExpect(t, engine)
+
+	assert.True(t, bot.Ready())
+	e.GET("/readyz").Expect().Status(http.StatusOK)
+
+	bot.Shutdown(time.Second)
+	assert.False(t, bot.Ready())
+	assert.False(t, bot.DebugInfo().Ready)
+	e.GET("/readyz").Expect().Status(http.StatusServiceUnavailable)
+
+	// booting again makes the bot ready
+	assert.NoError(t, bot.BootWithEngine("localhost:0", gin.New()))
+	defer bot.Shutdown(time.Second)
+	assert.True(t, bot.Ready())
+}
@@ -0,0 +1,147 @@
+package slackbot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"sync"
+	"time"
+)
+
+// How long the view published for a user is remembered. A user opening the tab after that has it published again.
+const homePublishedTTL = 24 * time.Hour
+
+// Renders the App Home tab of a user. teamID is empty when the render was requested with RefreshHome.
+type HomeRenderCallback = func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error)
+
+type home struct {
+	render HomeRenderCallback
+	// the app_home_opened registration publishing the view
+	handle Handle
+	// view last published per user; user IDs are unique across teams
+	published map[string]publishedHome
+	// when expired views are next removed from published
+	sweep time.Time
+	sync.Mutex
+}
+
+type publishedHome struct {
+	hash    string
+	expires time.Time
+}
+
+// publishedHash returns the hash of the view last published for a user, or an empty string once it expired, and must
+// be called with the lock held
+func (h *home) publishedHash(userID string, now time.Time) string {
+	published, exists := h.published[userID]
+	if !exists || !now.Before(published.expires) {
+		return ""
+	}
+	return published.hash
+}
+
+// setPublished remembers the view published for a user, removing expired views at most once per TTL, and must be
+// called with the lock held
+func (h *home) setPublished(userID string, hash string, now time.Time) {
+	h.published[userID] = publishedHome{hash: hash, expires: now.Add(homePublishedTTL)}
+	if now.Before(h.sweep) {
+		return
+	}
+	for user, published := range h.published {
+		if !now.Before(published.expires) {
+			delete(h.published, user)
+		}
+	}
+	h.sweep = now.Add(homePublishedTTL)
+}
+
+// Register a callback rendering each user's App Home tab. The view is published whenever a user opens the tab,
+// unless it is identical to the view last published for them, and on demand with RefreshHome.
+// Registering again replaces the previous callback. Unregistering the returned Handle with UnregisterEvent removes it.
+func (b *Bot) RegisterHome(render HomeRenderCallback) Handle {
+	h := &home{render: render, published: make(map[string]publishedHome)}
+	b.Lock()
+	var previous Handle
+	if b.home != nil {
+		previous = b.home.handle
+	}
+	b.home = h
+	b.Unlock()
+	if previous != 0 {
+		b.UnregisterEvent(previous)
+	}
+
+	handle := b.RegisterAppHomeOpenedEvent(func(bot *Bot, c AppHomeOpenedEventContainer) {
+		if c.Event.Tab != "" && c.Event.Tab != "home" {
+			return
+		}
+		// a user opening the tab for the first time has no view yet, whatever was published before
+		force := c.Event.View.ID == ""
+		if err := b.publishHome(c.Context(), c.APIEvent.TeamID, c.Event.User, force); err != nil {
+			b.logger().Error("Failed to publish App Home", Fields{"team_id": c.APIEvent.TeamID, "user_id": c.Event.User, "error": err})
+		}
+	})
+
+	b.Lock()
+	h.handle = handle
+	b.Unlock()
+	return handle
+}
+
+// Render and publish the App Home tab of a user, if it changed since it was last published
+func (b *Bot) RefreshHome(userID string) error {
+	return b.RefreshHomeContext(context.Background(), "", userID)
+}
+
+// Render and publish the App Home tab of a user in a team with a custom context, if it changed since it was last published
+func (b *Bot) RefreshHomeContext(ctx context.Context, teamID string, userID string) error {
+	return b.publishHome(ctx, teamID, userID, false)
+}
+
+func (b *Bot) publishHome(ctx context.Context, teamID string, userID string, force bool) error {
+	b.RLock()
+	h := b.home
+	b.RUnlock()
+	if h == nil {
+		return ErrNoHome
+	}
+
+	view, err := h.render(ctx, b, teamID, userID)
+	if err != nil {
+		return err
+	}
+	view.Type = slack.VTHomeTab
+
+	encoded, err := json.Marshal(view)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(encoded)
+	hash := hex.EncodeToString(sum[:])
+
+	h.Lock()
+	unchanged := h.publishedHash(userID, time.Now()) == hash
+	h.Unlock()
+	if unchanged && !force {
+		b.logger().Debug("App Home unchanged", Fields{"team_id": teamID, "user_id": userID})
+		return nil
+	}
+
+	if _, err := b.ApiForTeam(teamID).PublishViewContext(ctx, userID, view, ""); err != nil {
+		return err
+	}
+
+	h.Lock()
+	h.setPublished(userID, hash, time.Now())
+	h.Unlock()
+	return nil
+}
+
+// Register a callback for block actions in the App Home tab matching a BlockActionFilter.
+// Callback runs once for each matching action of an interaction and receives that action.
+func (b *Bot) RegisterHomeBlockAction(filter BlockActionFilter, callback BlockActionCallback) Handle {
+	filter.ContainerType = BlockActionContainerHome
+	return b.RegisterBlockAction(filter, callback)
+}
@@ -0,0 +1,211 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type publishViewRequest struct {
+	UserID string                   `json:"user_id"`
+	View   slack.HomeTabViewRequest `json:"view"`
+}
+
+// publishedViews decodes the views.publish calls a fake received
+func publishedViews(server *fakeSlack) []publishViewRequest {
+	var published []publishViewRequest
+	for _, call := range server.callsTo("views.publish") {
+		var request publishViewRequest
+		_ = json.Unmarshal(call.body, &request)
+		published = append(published, request)
+	}
+	return published
+}
+
+func newHomeView(text string) slack.HomeTabViewRequest {
+	return slack.HomeTabViewRequest{
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+		}},
+	}
+}
+
+func postAppHomeOpened(t *testing.T, engine *gin.Engine, event slackevents.AppHomeOpenedEvent) {
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEventWithData(slackevents.AppHomeOpened, event)).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestRegisterHomePublishesOnAppHomeOpened(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView("hello " + userID), nil
+	})
+	bot.prepareEngine(engine, false)
+
+	postAppHomeOpened(t, engine, slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home"})
+	postAppHomeOpened(t, engine, slackevents.AppHomeOpenedEvent{User: "U1", Tab: "messages"})
+
+	published := publishedViews(server)
+	if assert.Equal(t, 1, len(published)) {
+		assert.Equal(t, "U1", published[0].UserID)
+		assert.Equal(t, slack.VTHomeTab, published[0].View.Type)
+	}
+}
+
+func TestRegisterHomeSkipsUnchangedViews(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	text := "first"
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView(text), nil
+	})
+	bot.prepareEngine(engine, false)
+
+	opened := slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home", View: slack.View{ID: "V1"}}
+	postAppHomeOpened(t, engine, opened)
+	postAppHomeOpened(t, engine, opened)
+	assert.Equal(t, 1, len(publishedViews(server)))
+
+	// without a view the user has never seen the home tab, so it is published again
+	postAppHomeOpened(t, engine, slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home"})
+	assert.Equal(t, 2, len(publishedViews(server)))
+
+	text = "second"
+	postAppHomeOpened(t, engine, opened)
+	assert.Equal(t, 3, len(publishedViews(server)))
+}
+
+func TestRefreshHome(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot := server.newBot()
+	assert.Equal(t, ErrNoHome, bot.RefreshHome("U1"))
+
+	count := 0
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		count++
+		return newHomeView("count"), nil
+	})
+
+	assert.NoError(t, bot.RefreshHome("U1"))
+	assert.NoError(t, bot.RefreshHome("U1"))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, len(publishedViews(server)))
+}
+
+func TestUnregisterHome(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot := server.newBot()
+	handle := bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView("home"), nil
+	})
+	bot.UnregisterEvent(handle)
+
+	assert.Equal(t, ErrNoHome, bot.RefreshHome("U1"))
+	assert.Empty(t, publishedViews(server))
+	assert.Equal(t, 0, len(bot.events[slackevents.AppHomeOpened]))
+}
+
+func TestHomePublishedViewsExpire(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot := server.newBot()
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView("home"), nil
+	})
+	assert.NoError(t, bot.RefreshHome("U1"))
+
+	// once expired, an unchanged view is published again and other expired views are removed
+	h := bot.home
+	expired := publishedHome{hash: h.published["U1"].hash, expires: time.Now()}
+	h.published["U1"] = expired
+	h.published["U2"] = expired
+	h.sweep = time.Time{}
+	assert.NoError(t, bot.RefreshHome("U1"))
+
+	assert.Equal(t, 2, len(publishedViews(server)))
+	assert.Equal(t, 1, len(h.published))
+	assert.True(t, h.published["U1"].expires.After(time.Now()))
+}
+
+func TestRefreshHomeRenderError(t *testing.T) {
+	bot := newBot()
+	renderErr := errors.New("render failed")
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return slack.HomeTabViewRequest{}, renderErr
+	})
+
+	assert.Equal(t, renderErr, bot.RefreshHome("U1"))
+}
+
+func TestRegisterHomeBlockAction(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var matched []string
+	bot.RegisterHomeBlockAction(BlockActionFilter{ActionID: "refresh"}, func(bot *Bot, interaction slack.InteractionCallback, action *slack.BlockAction) {
+		matched = append(matched, interaction.View.ID)
+	})
+	bot.prepareEngine(engine, false)
+
+	actions := slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "refresh"}}}
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type:           slack.InteractionTypeBlockActions,
+		Container:      slack.Container{Type: "view"},
+		View:           slack.View{ID: "home", Type: slack.VTHomeTab},
+		ActionCallback: actions,
+	})
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type:           slack.InteractionTypeBlockActions,
+		Container:      slack.Container{Type: "view"},
+		View:           slack.View{ID: "modal", Type: slack.VTModal},
+		ActionCallback: actions,
+	})
+
+	assert.Equal(t, []string{"home"}, matched)
+}
+
+func TestRegisterHomeReplacesRenderer(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView("first"), nil
+	})
+	bot.RegisterHome(func(ctx context.Context, bot *Bot, teamID string, userID string) (slack.HomeTabViewRequest, error) {
+		return newHomeView("second"), nil
+	})
+	bot.prepareEngine(engine, false)
+
+	postAppHomeOpened(t, engine, slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home"})
+
+	published := publishedViews(server)
+	if assert.Equal(t, 1, len(published)) {
+		section := published[0].View.Blocks.BlockSet[0].(*slack.SectionBlock)
+		assert.Equal(t, "second", section.Text.Text)
+	}
+	assert.Equal(t, 1, len(bot.events[slackevents.AppHomeOpened]))
+}
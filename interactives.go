@@ -1,73 +1,246 @@
 package slackbot
 
 import (
+	"context"
+	"fmt"
 	"github.com/slack-go/slack"
+	"regexp"
+	"strings"
 )
 
 type InteractionCallback = func(bot *Bot, event slack.InteractionCallback)
 type ViewSubmissionInteractionCallback = func(bot *Bot, event slack.InteractionCallback) *slack.ViewSubmissionResponse
 
+// Callback which may return a response for Slack, such as a slack.ViewSubmissionResponse or a *slack.Msg, or nil for no response
+type InteractionResponseCallback = func(bot *Bot, event slack.InteractionCallback) interface{}
+
 // Register a callback for message_action interactions with a specific callbackId
-func (b *Bot) RegisterMessageActionInteraction(callbackId string, callback InteractionCallback) {
-	b.registerInteractive(slack.InteractionTypeMessageAction, func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
-		if interaction.CallbackID == callbackId {
-			callback(b, interaction)
-		}
+func (b *Bot) RegisterMessageActionInteraction(callbackId string, callback InteractionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeMessageAction, "callback_id="+callbackId, matchCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		callback(b, interaction)
 		return nil
 	})
 }
 
+// Register a callback for message_action interactions with a specific callbackId which may return a response
+func (b *Bot) RegisterMessageActionInteractionResponse(callbackId string, callback InteractionResponseCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeMessageAction, "callback_id="+callbackId, matchCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return callback(b, interaction)
+	})
+}
+
 // Register a callback for shortcut interactions with a specific callbackId
-func (b *Bot) RegisterShortcutInteraction(callbackId string, callback InteractionCallback) {
-	b.registerInteractive(slack.InteractionTypeShortcut, func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
-		if interaction.CallbackID == callbackId {
-			callback(b, interaction)
-		}
+func (b *Bot) RegisterShortcutInteraction(callbackId string, callback InteractionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeShortcut, "callback_id="+callbackId, matchCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		callback(b, interaction)
 		return nil
 	})
 }
 
-// Filter for RegisterBlockActionsInteraction
-//   - specify an ActionID to filter for only certain actions
-//   - specify a BlockID for filter for only certain blocks
+// Register a callback for shortcut interactions with a specific callbackId which may return a response
+func (b *Bot) RegisterShortcutInteractionResponse(callbackId string, callback InteractionResponseCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeShortcut, "callback_id="+callbackId, matchCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return callback(b, interaction)
+	})
+}
+
+// Container types of block actions
+const (
+	BlockActionContainerMessage = "message"
+	BlockActionContainerView    = "view"
+	BlockActionContainerHome    = "home"
+)
+
+// Filter for RegisterBlockActionsInteraction and RegisterBlockAction. Every set field must match.
+//   - specify an ActionID to filter for only certain actions, or ActionIDPrefix or ActionIDRegex to match several
+//   - specify a BlockID for filter for only certain blocks, or BlockIDPrefix or BlockIDRegex to match several
+//   - specify an ActionType such as "button" or "static_select" to filter for only certain elements
+//   - specify a ContainerType to filter for actions in a message, a modal view or the App Home
 type BlockActionFilter struct {
-	ActionID string
-	BlockID  string
-}
-
-// Register a callback for block_actions interactions with a specified BlockActionFilter
-func (b *Bot) RegisterBlockActionsInteraction(filter BlockActionFilter, callback InteractionCallback) {
-	b.registerInteractive(slack.InteractionTypeBlockActions, func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
-		actions := interaction.ActionCallback.BlockActions
-		if len(actions) > 0 {
-			action := actions[0]
-			actionMatch := (filter.ActionID == "") || (action.ActionID == filter.ActionID)
-			blockMatch := (filter.BlockID == "") || (action.BlockID == filter.BlockID)
-			if actionMatch && blockMatch {
-				callback(b, interaction)
-			}
+	ActionID       string
+	ActionIDPrefix string
+	ActionIDRegex  *regexp.Regexp
+	BlockID        string
+	BlockIDPrefix  string
+	BlockIDRegex   *regexp.Regexp
+	ActionType     string
+	ContainerType  string
+}
+
+func (f BlockActionFilter) String() string {
+	description := fmt.Sprintf("action_id=%s block_id=%s", f.ActionID, f.BlockID)
+	if f.ActionIDPrefix != "" {
+		description += " action_id_prefix=" + f.ActionIDPrefix
+	}
+	if f.ActionIDRegex != nil {
+		description += " action_id_regex=" + f.ActionIDRegex.String()
+	}
+	if f.BlockIDPrefix != "" {
+		description += " block_id_prefix=" + f.BlockIDPrefix
+	}
+	if f.BlockIDRegex != nil {
+		description += " block_id_regex=" + f.BlockIDRegex.String()
+	}
+	if f.ActionType != "" {
+		description += " action_type=" + f.ActionType
+	}
+	if f.ContainerType != "" {
+		description += " container_type=" + f.ContainerType
+	}
+	return description
+}
+
+type BlockActionCallback = func(bot *Bot, interaction slack.InteractionCallback, action *slack.BlockAction)
+
+// Register a callback for block_actions interactions with a specified BlockActionFilter.
+// Callback runs once per interaction when any of its actions match.
+func (b *Bot) RegisterBlockActionsInteraction(filter BlockActionFilter, callback InteractionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeBlockActions, filter.String(), filter.match, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		callback(b, interaction)
+		return nil
+	})
+}
+
+// Register a callback for block actions matching a BlockActionFilter.
+// Callback runs once for each matching action of an interaction and receives that action.
+func (b *Bot) RegisterBlockAction(filter BlockActionFilter, callback BlockActionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeBlockActions, filter.String(), filter.match, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		for _, action := range filter.matchingActions(interaction) {
+			callback(b, interaction, action)
 		}
 		return nil
 	})
 }
 
+// Register a callback for block_actions interactions with a specified BlockActionFilter which may return a response.
+// Callback runs once per interaction when any of its actions match.
+func (b *Bot) RegisterBlockActionsInteractionResponse(filter BlockActionFilter, callback InteractionResponseCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeBlockActions, filter.String(), filter.match, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return callback(b, interaction)
+	})
+}
+
+func (f BlockActionFilter) match(interaction slack.InteractionCallback) bool {
+	return len(f.matchingActions(interaction)) > 0
+}
+
+// matchingActions returns every action of an interaction which matches the filter
+func (f BlockActionFilter) matchingActions(interaction slack.InteractionCallback) []*slack.BlockAction {
+	if f.ContainerType != "" && f.ContainerType != blockActionContainerType(interaction) {
+		return nil
+	}
+
+	var actions []*slack.BlockAction
+	for _, action := range interaction.ActionCallback.BlockActions {
+		if action != nil && f.matchAction(action) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+func (f BlockActionFilter) matchAction(action *slack.BlockAction) bool {
+	return matchID(action.ActionID, f.ActionID, f.ActionIDPrefix, f.ActionIDRegex) &&
+		matchID(action.BlockID, f.BlockID, f.BlockIDPrefix, f.BlockIDRegex) &&
+		(f.ActionType == "" || string(action.Type) == f.ActionType)
+}
+
+func matchID(id string, exact string, prefix string, regex *regexp.Regexp) bool {
+	return (exact == "" || id == exact) &&
+		(prefix == "" || strings.HasPrefix(id, prefix)) &&
+		(regex == nil || regex.MatchString(id))
+}
+
+// blockActionContainerType distinguishes App Home views from modals, which Slack both calls view containers
+func blockActionContainerType(interaction slack.InteractionCallback) string {
+	if interaction.Container.Type == BlockActionContainerView && interaction.View.Type == slack.VTHomeTab {
+		return BlockActionContainerHome
+	}
+	return interaction.Container.Type
+}
+
 // Register a callback for view_submission interactions with a specific callbackId
 // Callback may return a slack.ViewSubmissionResponse or nil for no response
-func (b *Bot) RegisterViewSubmissionInteraction(callbackId string, callback ViewSubmissionInteractionCallback) {
-	b.registerInteractive(slack.InteractionTypeViewSubmission, func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
-		if interaction.View.CallbackID == callbackId {
-			return callback(b, interaction)
-		}
-		return nil
+func (b *Bot) RegisterViewSubmissionInteraction(callbackId string, callback ViewSubmissionInteractionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeViewSubmission, "callback_id="+callbackId, matchViewCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return callback(b, interaction)
 	})
 }
 
 // Register a callback for view_closed interactions with a specific callbackId
-func (b *Bot) RegisterViewClosedInteraction(callbackId string, callback InteractionCallback) {
-	b.registerInteractive(slack.InteractionTypeViewClosed, func(bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
-		if interaction.View.CallbackID == callbackId {
-			callback(b, interaction)
-		}
+func (b *Bot) RegisterViewClosedInteraction(callbackId string, callback InteractionCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeViewClosed, "callback_id="+callbackId, matchViewCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		callback(b, interaction)
 		return nil
 	})
 }
+
+// Register a callback for view_closed interactions with a specific callbackId which may return a response
+func (b *Bot) RegisterViewClosedInteractionResponse(callbackId string, callback InteractionResponseCallback) Handle {
+	return b.registerDescribedInteractive(slack.InteractionTypeViewClosed, "callback_id="+callbackId, matchViewCallbackID(callbackId), func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+		return callback(b, interaction)
+	})
+}
+
+// Register a callback for every interaction of a type which receives the context of the request.
+// Callback may return a response for Slack, such as a slack.ViewSubmissionResponse, or nil for no response
+func (b *Bot) RegisterInteractionContext(interactionType slack.InteractionType, callback InteractionContextCallback) Handle {
+	return b.registerInteractive(interactionType, callback)
+}
+
+func matchCallbackID(callbackId string) func(interaction slack.InteractionCallback) bool {
+	return func(interaction slack.InteractionCallback) bool {
+		return interaction.CallbackID == callbackId
+	}
+}
+
+func matchViewCallbackID(callbackId string) func(interaction slack.InteractionCallback) bool {
+	return func(interaction slack.InteractionCallback) bool {
+		return interaction.View.CallbackID == callbackId
+	}
+}
+
+// composeInteractionResponses picks the response sent to Slack when several callbacks of an interaction respond.
+// Every matching callback runs in registration order, then:
+//   - view submission "errors" responses win over any other response and are merged, so several validators may each flag
+//     fields; the first message for a block is kept
+//   - otherwise the first response wins
+//
+// The number of responses which were not sent is returned so it can be logged.
+func composeInteractionResponses(responses []interface{}) (response interface{}, discarded int) {
+	var merged *slack.ViewSubmissionResponse
+	errors := 0
+	for _, r := range responses {
+		submission, ok := viewSubmissionResponse(r)
+		if !ok || submission.ResponseAction != slack.RAErrors {
+			continue
+		}
+		errors++
+		if merged == nil {
+			merged = slack.NewErrorsViewSubmissionResponse(make(map[string]string))
+		}
+		for block, message := range submission.Errors {
+			if _, exists := merged.Errors[block]; !exists {
+				merged.Errors[block] = message
+			}
+		}
+	}
+	if merged != nil {
+		return merged, len(responses) - errors
+	}
+
+	if len(responses) == 0 {
+		return nil, 0
+	}
+	return responses[0], len(responses) - 1
+}
+
+func viewSubmissionResponse(response interface{}) (*slack.ViewSubmissionResponse, bool) {
+	switch r := response.(type) {
+	case *slack.ViewSubmissionResponse:
+		return r, true
+	case slack.ViewSubmissionResponse:
+		return &r, true
+	}
+	return nil, false
+}
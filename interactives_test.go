@@ -6,6 +6,7 @@ import (
 	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"regexp"
 	"testing"
 )
 
@@ -210,3 +211,187 @@ func TestRegisterViewClosedInteraction(t *testing.T) {
 	assert.True(t, callback1Hit)
 	assert.False(t, callback2Hit)
 }
+
+func TestRegisterBlockActionsInteractionMatchesAnyAction(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	hits := 0
+	bot.RegisterBlockActionsInteraction(BlockActionFilter{ActionID: "action2"}, func(bot *Bot, event slack.InteractionCallback) {
+		hits++
+	})
+	bot.prepareEngine(engine, false)
+
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{{ActionID: "action1"}, {ActionID: "action2"}},
+		},
+	})
+
+	assert.Equal(t, 1, hits)
+}
+
+func TestRegisterBlockActionReceivesEachMatchingAction(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var matched []string
+	bot.RegisterBlockAction(BlockActionFilter{ActionIDPrefix: "approve_"}, func(bot *Bot, interaction slack.InteractionCallback, action *slack.BlockAction) {
+		matched = append(matched, action.ActionID)
+	})
+	bot.prepareEngine(engine, false)
+
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{{ActionID: "approve_1"}, {ActionID: "deny_1"}, {ActionID: "approve_2"}},
+		},
+	})
+
+	assert.Equal(t, []string{"approve_1", "approve_2"}, matched)
+}
+
+func TestBlockActionFilterRegex(t *testing.T) {
+	filter := BlockActionFilter{BlockIDRegex: regexp.MustCompile(`^ticket-\d+$`), ActionIDRegex: regexp.MustCompile(`close|reopen`)}
+
+	assert.True(t, filter.matchAction(&slack.BlockAction{BlockID: "ticket-12", ActionID: "close"}))
+	assert.False(t, filter.matchAction(&slack.BlockAction{BlockID: "ticket-12", ActionID: "assign"}))
+	assert.False(t, filter.matchAction(&slack.BlockAction{BlockID: "ticket-abc", ActionID: "close"}))
+}
+
+func TestBlockActionFilterActionType(t *testing.T) {
+	filter := BlockActionFilter{ActionType: "static_select"}
+
+	assert.True(t, filter.matchAction(&slack.BlockAction{Type: "static_select"}))
+	assert.False(t, filter.matchAction(&slack.BlockAction{Type: "button"}))
+}
+
+func TestBlockActionFilterContainerType(t *testing.T) {
+	actions := slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "refresh"}}}
+	message := slack.InteractionCallback{Container: slack.Container{Type: "message"}, ActionCallback: actions}
+	modal := slack.InteractionCallback{Container: slack.Container{Type: "view"}, View: slack.View{Type: slack.VTModal}, ActionCallback: actions}
+	home := slack.InteractionCallback{Container: slack.Container{Type: "view"}, View: slack.View{Type: slack.VTHomeTab}, ActionCallback: actions}
+
+	homeOnly := BlockActionFilter{ContainerType: BlockActionContainerHome}
+	assert.False(t, homeOnly.match(message))
+	assert.False(t, homeOnly.match(modal))
+	assert.True(t, homeOnly.match(home))
+
+	viewOnly := BlockActionFilter{ContainerType: BlockActionContainerView}
+	assert.True(t, viewOnly.match(modal))
+	assert.False(t, viewOnly.match(home))
+
+	messageOnly := BlockActionFilter{ContainerType: BlockActionContainerMessage}
+	assert.True(t, messageOnly.match(message))
+}
+
+func TestBlockActionFilterString(t *testing.T) {
+	assert.Equal(t, "action_id=a block_id=", BlockActionFilter{ActionID: "a"}.String())
+	assert.Equal(t, "action_id= block_id= action_id_prefix=p_ container_type=home", BlockActionFilter{ActionIDPrefix: "p_", ContainerType: BlockActionContainerHome}.String())
+}
+
+func TestRegisterBlockActionsInteractionResponse(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	bot.RegisterBlockActionsInteractionResponse(BlockActionFilter{ActionID: "next"}, func(bot *Bot, event slack.InteractionCallback) interface{} {
+		return slack.NewUpdateViewSubmissionResponse(&slack.ModalViewRequest{Type: slack.VTModal, CallbackID: "step2"})
+	})
+	bot.prepareEngine(engine, false)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{
+		Type:           slack.InteractionTypeBlockActions,
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{{ActionID: "next"}}},
+	})
+
+	e := getHttpExpect(t, engine)
+	object := e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	object.ValueEqual("response_action", "update")
+	object.Path("$.view.callback_id").Equal("step2")
+}
+
+func TestRegisterInteractionResponseVariants(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	bot.RegisterShortcutInteractionResponse("shortcut", func(bot *Bot, event slack.InteractionCallback) interface{} {
+		return &slack.Msg{Text: "shortcut"}
+	})
+	bot.RegisterMessageActionInteractionResponse("action", func(bot *Bot, event slack.InteractionCallback) interface{} {
+		return &slack.Msg{Text: "action"}
+	})
+	bot.RegisterViewClosedInteractionResponse("closed", func(bot *Bot, event slack.InteractionCallback) interface{} {
+		return &slack.Msg{Text: "closed"}
+	})
+	bot.prepareEngine(engine, false)
+
+	interactions := map[string]slack.InteractionCallback{
+		"shortcut": {Type: slack.InteractionTypeShortcut, CallbackID: "shortcut"},
+		"action":   {Type: slack.InteractionTypeMessageAction, CallbackID: "action"},
+		"closed":   {Type: slack.InteractionTypeViewClosed, View: slack.View{CallbackID: "closed"}},
+	}
+	for text, interaction := range interactions {
+		payload, _ := json.Marshal(interaction)
+		e := getHttpExpect(t, engine)
+		e.POST("/slack/interactives").
+			WithFormField("payload", string(payload)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().ValueEqual("text", text)
+	}
+}
+
+func TestEveryMatchingCallbackRunsWhenOneResponds(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var order []string
+	bot.RegisterShortcutInteractionResponse("shortcut", func(bot *Bot, event slack.InteractionCallback) interface{} {
+		order = append(order, "first")
+		return &slack.Msg{Text: "first"}
+	})
+	bot.RegisterShortcutInteractionResponse("shortcut", func(bot *Bot, event slack.InteractionCallback) interface{} {
+		order = append(order, "second")
+		return &slack.Msg{Text: "second"}
+	})
+	bot.prepareEngine(engine, false)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "shortcut"})
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("text", "first")
+
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestComposeInteractionResponses(t *testing.T) {
+	response, discarded := composeInteractionResponses(nil)
+	assert.Nil(t, response)
+	assert.Equal(t, 0, discarded)
+
+	clearResponse := slack.NewClearViewSubmissionResponse()
+	update := slack.NewUpdateViewSubmissionResponse(&slack.ModalViewRequest{})
+	response, discarded = composeInteractionResponses([]interface{}{clearResponse, update})
+	assert.Same(t, clearResponse, response)
+	assert.Equal(t, 1, discarded)
+
+	response, discarded = composeInteractionResponses([]interface{}{
+		clearResponse,
+		slack.NewErrorsViewSubmissionResponse(map[string]string{"name": "required", "email": "invalid"}),
+		*slack.NewErrorsViewSubmissionResponse(map[string]string{"name": "too short", "age": "required"}),
+	})
+	assert.Equal(t, 1, discarded)
+	assert.Equal(t, slack.NewErrorsViewSubmissionResponse(map[string]string{
+		"name":  "required",
+		"email": "invalid",
+		"age":   "required",
+	}), response)
+}
@@ -0,0 +1,176 @@
+package slackbot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"sort"
+	"time"
+)
+
+const requestIDContextKey = "slackbot.request_id"
+const logFieldsContextKey = "slackbot.log_fields"
+
+// Structured fields attached to a log line
+type Fields = map[string]interface{}
+
+// Logger is the structured logging interface used by the bot. Adapters are provided for
+// logrus (NewLogrusLogger), zap (NewZapLogger), log/slog (NewSlogLogger) and for discarding logs (NopLogger).
+type Logger interface {
+	Debug(msg string, fields Fields)
+	Info(msg string, fields Fields)
+	Warn(msg string, fields Fields)
+	Error(msg string, fields Fields)
+}
+
+// LogrusLogger adapts a logrus.Logger to the Logger interface
+type LogrusLogger struct {
+	logger *logrus.Logger
+}
+
+// Create a Logger which writes to a logrus.Logger
+func NewLogrusLogger(logger *logrus.Logger) LogrusLogger {
+	return LogrusLogger{logger: logger}
+}
+
+func (l LogrusLogger) Debug(msg string, fields Fields) {
+	l.logger.WithFields(fields).Debug(msg)
+}
+
+func (l LogrusLogger) Info(msg string, fields Fields) {
+	l.logger.WithFields(fields).Info(msg)
+}
+
+func (l LogrusLogger) Warn(msg string, fields Fields) {
+	l.logger.WithFields(fields).Warn(msg)
+}
+
+func (l LogrusLogger) Error(msg string, fields Fields) {
+	l.logger.WithFields(fields).Error(msg)
+}
+
+// NopLogger discards all logs
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, Fields) {}
+func (NopLogger) Info(string, Fields)  {}
+func (NopLogger) Warn(string, Fields)  {}
+func (NopLogger) Error(string, Fields) {}
+
+// sortedKeys returns the keys of fields in a stable order for adapters which take ordered key/value pairs
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// addLogFields attaches fields describing the dispatched request to the request's log lines
+func addLogFields(ctx *gin.Context, fields Fields) Fields {
+	merged := Fields{"request_id": ctx.GetString(requestIDContextKey)}
+	if existing, exists := ctx.Get(logFieldsContextKey); exists {
+		for key, value := range existing.(Fields) {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		if value != "" {
+			merged[key] = value
+		}
+	}
+	ctx.Set(logFieldsContextKey, merged)
+	return merged
+}
+
+// newRequestLoggerMiddleware assigns each request an ID and logs it once handled, along with any fields added by the dispatcher
+func (b *Bot) newRequestLoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader("X-Request-Id")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDContextKey, requestID)
+		c.Header("X-Request-Id", requestID)
+
+		c.Next()
+
+		fields := addLogFields(c, Fields{
+			"method":    c.Request.Method,
+			"path":      c.Request.URL.Path,
+			"status":    c.Writer.Status(),
+			"latency":   time.Since(start).String(),
+			"client_ip": c.ClientIP(),
+		})
+		if retry := c.GetHeader("X-Slack-Retry-Num"); retry != "" {
+			fields["retry_num"] = retry
+		}
+
+		if len(c.Errors) > 0 {
+			fields["error"] = c.Errors.String()
+			if c.Writer.Status() >= 500 {
+				b.logger().Error("Request failed", fields)
+			} else {
+				b.logger().Warn("Request failed", fields)
+			}
+			return
+		}
+		b.logger().Info("Request handled", fields)
+	}
+}
+
+func commandFields(command slack.SlashCommand) Fields {
+	return Fields{
+		"team_id":    command.TeamID,
+		"user_id":    command.UserID,
+		"channel_id": command.ChannelID,
+		"command":    command.Command,
+	}
+}
+
+func interactionFields(interaction slack.InteractionCallback) Fields {
+	return Fields{
+		"team_id":          interaction.Team.ID,
+		"user_id":          interaction.User.ID,
+		"channel_id":       interaction.Channel.ID,
+		"interaction_type": string(interaction.Type),
+		"callback_id":      interactionCallbackID(interaction),
+	}
+}
+
+// eventFields reads the user and channel from the raw inner event since their location is not common to every event type
+func eventFields(event slackevents.EventsAPIEvent, body []byte) Fields {
+	var envelope struct {
+		EventID string `json:"event_id"`
+		Event   struct {
+			User    json.RawMessage `json:"user"`
+			Channel json.RawMessage `json:"channel"`
+		} `json:"event"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+
+	var user, channel string
+	_ = json.Unmarshal(envelope.Event.User, &user)
+	_ = json.Unmarshal(envelope.Event.Channel, &channel)
+
+	return Fields{
+		"team_id":    event.TeamID,
+		"user_id":    user,
+		"channel_id": channel,
+		"event_type": event.InnerEvent.Type,
+		"event_id":   envelope.EventID,
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+package slackbot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger adapts a slog.Logger to the Logger interface
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// Create a Logger which writes to a slog.Logger
+func NewSlogLogger(logger *slog.Logger) SlogLogger {
+	return SlogLogger{logger: logger}
+}
+
+func (l SlogLogger) log(level slog.Level, msg string, fields Fields) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, key := range sortedKeys(fields) {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (l SlogLogger) Debug(msg string, fields Fields) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l SlogLogger) Info(msg string, fields Fields) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l SlogLogger) Warn(msg string, fields Fields) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l SlogLogger) Error(msg string, fields Fields) {
+	l.log(slog.LevelError, msg, fields)
+}
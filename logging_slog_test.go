@@ -0,0 +1,20 @@
+//go:build go1.21
+// +build go1.21
+
+package slackbot
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logger.Debug("debug", Fields{"b": 2, "a": "value"})
+
+	assert.Contains(t, buffer.String(), "level=DEBUG msg=debug a=value b=2")
+}
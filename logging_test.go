@@ -0,0 +1,122 @@
+package slackbot
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields Fields
+}
+
+type memoryLogger struct {
+	entries []logEntry
+	sync.Mutex
+}
+
+func (l *memoryLogger) log(level string, msg string, fields Fields) {
+	l.Lock()
+	defer l.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l *memoryLogger) Debug(msg string, fields Fields) { l.log("debug", msg, fields) }
+func (l *memoryLogger) Info(msg string, fields Fields)  { l.log("info", msg, fields) }
+func (l *memoryLogger) Warn(msg string, fields Fields)  { l.log("warn", msg, fields) }
+func (l *memoryLogger) Error(msg string, fields Fields) { l.log("error", msg, fields) }
+
+func (l *memoryLogger) find(msg string) *logEntry {
+	l.Lock()
+	defer l.Unlock()
+	for _, entry := range l.entries {
+		if entry.msg == msg {
+			return &entry
+		}
+	}
+	return nil
+}
+
+func TestRequestLogIncludesSlackFields(t *testing.T) {
+	logger := &memoryLogger{}
+	bot := NewBot("token", "secret", OptionLogger(logger))
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+
+	body := []byte("command=%2Ftest&team_id=T1&user_id=U1&channel_id=C1")
+	request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", body)
+	request.Header.Set("X-Request-Id", "request1")
+	response := httptest.NewRecorder()
+	bot.Handler().ServeHTTP(response, request)
+
+	assert.Equal(t, "request1", response.Header().Get("X-Request-Id"))
+
+	dispatch := logger.find("Dispatching command")
+	assert.NotNil(t, dispatch)
+	assert.Equal(t, "request1", dispatch.fields["request_id"])
+
+	entry := logger.find("Request handled")
+	assert.NotNil(t, entry)
+	assert.Equal(t, "info", entry.level)
+	assert.Equal(t, "request1", entry.fields["request_id"])
+	assert.Equal(t, "T1", entry.fields["team_id"])
+	assert.Equal(t, "U1", entry.fields["user_id"])
+	assert.Equal(t, "C1", entry.fields["channel_id"])
+	assert.Equal(t, "/test", entry.fields["command"])
+	assert.Equal(t, http.StatusOK, entry.fields["status"])
+}
+
+func TestRequestLogGeneratesRequestIDAndLogsErrors(t *testing.T) {
+	logger := &memoryLogger{}
+	bot := NewBot("token", "secret", OptionLogger(logger))
+
+	request := newSignedRequest(t, "wrong", "/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Ftest"))
+	response := httptest.NewRecorder()
+	bot.Handler().ServeHTTP(response, request)
+
+	entry := logger.find("Request failed")
+	assert.NotNil(t, entry)
+	assert.Equal(t, "warn", entry.level)
+	assert.NotEmpty(t, entry.fields["request_id"])
+	assert.Equal(t, response.Header().Get("X-Request-Id"), entry.fields["request_id"])
+	assert.Contains(t, entry.fields["error"], "signing signature")
+}
+
+func TestLogrusLogger(t *testing.T) {
+	logrusLogger, hook := test.NewNullLogger()
+	logrusLogger.SetLevel(logrus.DebugLevel)
+	logger := NewLogrusLogger(logrusLogger)
+
+	logger.Debug("debug", Fields{"key": "value"})
+	logger.Error("error", nil)
+
+	assert.Equal(t, 2, len(hook.Entries))
+	assert.Equal(t, logrus.DebugLevel, hook.Entries[0].Level)
+	assert.Equal(t, "value", hook.Entries[0].Data["key"])
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+}
+
+func TestZapLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewZapLogger(zap.New(core))
+
+	logger.Info("info", Fields{"key": "value"})
+	logger.Warn("warn", nil)
+
+	entries := logs.AllUntimed()
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "value", entries[0].ContextMap()["key"])
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+}
@@ -0,0 +1,37 @@
+package slackbot
+
+import "go.uber.org/zap"
+
+// ZapLogger adapts a zap.Logger to the Logger interface
+type ZapLogger struct {
+	logger *zap.Logger
+}
+
+// Create a Logger which writes to a zap.Logger
+func NewZapLogger(logger *zap.Logger) ZapLogger {
+	return ZapLogger{logger: logger}
+}
+
+func (l ZapLogger) fields(fields Fields) []zap.Field {
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, key := range sortedKeys(fields) {
+		zapFields = append(zapFields, zap.Any(key, fields[key]))
+	}
+	return zapFields
+}
+
+func (l ZapLogger) Debug(msg string, fields Fields) {
+	l.logger.Debug(msg, l.fields(fields)...)
+}
+
+func (l ZapLogger) Info(msg string, fields Fields) {
+	l.logger.Info(msg, l.fields(fields)...)
+}
+
+func (l ZapLogger) Warn(msg string, fields Fields) {
+	l.logger.Warn(msg, l.fields(fields)...)
+}
+
+func (l ZapLogger) Error(msg string, fields Fields) {
+	l.logger.Error(msg, l.fields(fields)...)
+}
@@ -0,0 +1,86 @@
+package slackbot
+
+import (
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Channel types of message events
+const (
+	ChannelTypeChannel = "channel"
+	ChannelTypeGroup   = "group"
+	ChannelTypeIM      = "im"
+	ChannelTypeMPIM    = "mpim"
+)
+
+// Filter for RegisterFilteredMessageEvent. Empty fields match every message.
+//   - specify Subtypes to receive only those subtypes, use "" for messages without a subtype
+//   - specify ExcludeSubtypes to ignore subtypes such as bot_message or message_changed
+//   - specify ChannelTypes to receive only messages from im, mpim, channel or group conversations
+//   - specify ChannelIDs to receive only messages from certain channels
+//   - set ThreadsOnly to receive only thread replies, or TopLevelOnly to receive only messages outside of threads
+//   - specify Users to receive only messages from those users, or ExcludeUsers to ignore them
+type MessageFilter struct {
+	Subtypes        []string
+	ExcludeSubtypes []string
+	ChannelTypes    []string
+	ChannelIDs      []string
+	ThreadsOnly     bool
+	TopLevelOnly    bool
+	Users           []string
+	ExcludeUsers    []string
+}
+
+// Register a callback for message events which match a MessageFilter
+func (b *Bot) RegisterFilteredMessageEvent(filter MessageFilter, callback MessageEventCallback) Handle {
+	return b.RegisterMessageEvent(func(bot *Bot, c MessageEventContainer) {
+		if filter.Match(c.Event) {
+			callback(bot, c)
+		}
+	})
+}
+
+// Match reports whether a message event passes the filter
+func (f MessageFilter) Match(event slackevents.MessageEvent) bool {
+	if len(f.Subtypes) > 0 && !contains(f.Subtypes, event.SubType) {
+		return false
+	}
+	if contains(f.ExcludeSubtypes, event.SubType) {
+		return false
+	}
+	if len(f.ChannelTypes) > 0 && !contains(f.ChannelTypes, event.ChannelType) {
+		return false
+	}
+	if len(f.ChannelIDs) > 0 && !contains(f.ChannelIDs, event.Channel) {
+		return false
+	}
+
+	reply := isThreadReply(event)
+	if f.ThreadsOnly && !reply {
+		return false
+	}
+	if f.TopLevelOnly && reply {
+		return false
+	}
+
+	if len(f.Users) > 0 && !contains(f.Users, event.User) {
+		return false
+	}
+	if contains(f.ExcludeUsers, event.User) {
+		return false
+	}
+	return true
+}
+
+// isThreadReply reports whether a message was posted in a thread; the parent message of a thread is not a reply
+func isThreadReply(event slackevents.MessageEvent) bool {
+	return event.ThreadTimeStamp != "" && event.ThreadTimeStamp != event.TimeStamp
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package slackbot
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestMessageFilterEmptyMatchesEverything(t *testing.T) {
+	assert.True(t, MessageFilter{}.Match(slackevents.MessageEvent{}))
+	assert.True(t, MessageFilter{}.Match(slackevents.MessageEvent{SubType: "bot_message", ChannelType: ChannelTypeIM}))
+}
+
+func TestMessageFilterSubtypes(t *testing.T) {
+	plain := MessageFilter{Subtypes: []string{""}}
+	assert.True(t, plain.Match(slackevents.MessageEvent{}))
+	assert.False(t, plain.Match(slackevents.MessageEvent{SubType: "channel_join"}))
+
+	excluded := MessageFilter{ExcludeSubtypes: []string{"bot_message", "message_changed"}}
+	assert.True(t, excluded.Match(slackevents.MessageEvent{SubType: "file_share"}))
+	assert.False(t, excluded.Match(slackevents.MessageEvent{SubType: "message_changed"}))
+}
+
+func TestMessageFilterChannels(t *testing.T) {
+	filter := MessageFilter{ChannelTypes: []string{ChannelTypeIM, ChannelTypeMPIM}, ChannelIDs: []string{"D1"}}
+
+	assert.True(t, filter.Match(slackevents.MessageEvent{ChannelType: ChannelTypeIM, Channel: "D1"}))
+	assert.False(t, filter.Match(slackevents.MessageEvent{ChannelType: ChannelTypeIM, Channel: "D2"}))
+	assert.False(t, filter.Match(slackevents.MessageEvent{ChannelType: ChannelTypeChannel, Channel: "D1"}))
+}
+
+func TestMessageFilterThreads(t *testing.T) {
+	topLevel := slackevents.MessageEvent{TimeStamp: "1.1"}
+	parent := slackevents.MessageEvent{TimeStamp: "1.1", ThreadTimeStamp: "1.1"}
+	reply := slackevents.MessageEvent{TimeStamp: "1.2", ThreadTimeStamp: "1.1"}
+
+	threadsOnly := MessageFilter{ThreadsOnly: true}
+	assert.False(t, threadsOnly.Match(topLevel))
+	assert.False(t, threadsOnly.Match(parent))
+	assert.True(t, threadsOnly.Match(reply))
+
+	topLevelOnly := MessageFilter{TopLevelOnly: true}
+	assert.True(t, topLevelOnly.Match(topLevel))
+	assert.True(t, topLevelOnly.Match(parent))
+	assert.False(t, topLevelOnly.Match(reply))
+}
+
+func TestMessageFilterUsers(t *testing.T) {
+	allowed := MessageFilter{Users: []string{"U1"}}
+	assert.True(t, allowed.Match(slackevents.MessageEvent{User: "U1"}))
+	assert.False(t, allowed.Match(slackevents.MessageEvent{User: "U2"}))
+
+	denied := MessageFilter{ExcludeUsers: []string{"U1"}}
+	assert.False(t, denied.Match(slackevents.MessageEvent{User: "U1"}))
+	assert.True(t, denied.Match(slackevents.MessageEvent{User: "U2"}))
+}
+
+func TestRegisterFilteredMessageEvent(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var received []string
+	bot.RegisterFilteredMessageEvent(MessageFilter{ExcludeSubtypes: []string{"bot_message"}}, func(bot *Bot, c MessageEventContainer) {
+		received = append(received, c.Event.Text)
+	})
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEventWithData(slackevents.Message, slackevents.MessageEvent{Text: "from a person"})).
+		Expect().
+		Status(http.StatusOK)
+	e.POST("/slack/events").
+		WithJSON(newFakeEventWithData(slackevents.Message, slackevents.MessageEvent{Text: "from a bot", SubType: "bot_message"})).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Equal(t, []string{"from a person"}, received)
+}
@@ -0,0 +1,174 @@
+package slackbot
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	dispatchKindCommand     = "command"
+	dispatchKindEvent       = "event"
+	dispatchKindInteraction = "interaction"
+	dispatchKindSelectMenu  = "select_menu"
+)
+
+type metrics struct {
+	dispatches           *prometheus.CounterVec
+	dispatchDuration     *prometheus.HistogramVec
+	verificationFailures prometheus.Counter
+	eventRetries         prometheus.Counter
+	panics               *prometheus.CounterVec
+	apiCalls             *prometheus.CounterVec
+	apiDuration          *prometheus.HistogramVec
+	rateLimitRetries     *prometheus.CounterVec
+	rateLimitDrops       *prometheus.CounterVec
+	appRateLimited       *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	m := &metrics{
+		dispatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "dispatches_total",
+			Help:      "Commands, events, interactions and select menus dispatched to callbacks.",
+		}, []string{"kind", "name", "callback_id"}),
+		dispatchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "slackbot",
+			Name:      "dispatch_duration_seconds",
+			Help:      "Time spent running callbacks for a dispatched request.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"kind", "name", "callback_id"}),
+		verificationFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "verification_failures_total",
+			Help:      "Requests rejected because their Slack signature could not be verified.",
+		}),
+		eventRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "event_retries_total",
+			Help:      "Events API deliveries which Slack marked as retries with X-Slack-Retry-Num.",
+		}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "panics_total",
+			Help:      "Panics recovered while handling requests.",
+		}, []string{"path"}),
+		apiCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "api_calls_total",
+			Help:      "Outbound requests to the Slack Web API.",
+		}, []string{"method", "status"}),
+		apiDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "slackbot",
+			Name:      "api_call_duration_seconds",
+			Help:      "Latency of outbound requests to the Slack Web API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		rateLimitRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "api_rate_limit_retries_total",
+			Help:      "Outbound Web API calls retried after Slack answered with HTTP 429.",
+		}, []string{"method"}),
+		rateLimitDrops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "api_rate_limit_drops_total",
+			Help:      "Outbound Web API calls dropped by the rate limiter.",
+		}, []string{"method"}),
+		appRateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "slackbot",
+			Name:      "app_rate_limited_total",
+			Help:      "app_rate_limited events received, after which Slack drops events for the rest of the minute.",
+		}, []string{"team_id"}),
+	}
+
+	registerer.MustRegister(
+		m.dispatches,
+		m.dispatchDuration,
+		m.verificationFailures,
+		m.eventRetries,
+		m.panics,
+		m.apiCalls,
+		m.apiDuration,
+		m.rateLimitRetries,
+		m.rateLimitDrops,
+		m.appRateLimited,
+	)
+
+	return m
+}
+
+// observeDispatch records a dispatch which started at start. It is a no-op when metrics are disabled.
+func (b *Bot) observeDispatch(kind string, name string, callbackID string, start time.Time) {
+	if b.metrics == nil {
+		return
+	}
+	b.metrics.dispatches.WithLabelValues(kind, name, callbackID).Inc()
+	b.metrics.dispatchDuration.WithLabelValues(kind, name, callbackID).Observe(time.Since(start).Seconds())
+}
+
+func (b *Bot) newMetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				b.metrics.panics.WithLabelValues(c.FullPath()).Inc()
+				panic(r)
+			}
+		}()
+
+		if c.GetHeader("X-Slack-Retry-Num") != "" {
+			b.metrics.eventRetries.Inc()
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized {
+			b.metrics.verificationFailures.Inc()
+		}
+	}
+}
+
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics *metrics
+	apiURL  string
+}
+
+func (t *metricsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	method := apiMethod(request, t.apiURL)
+	start := time.Now()
+
+	response, err := t.next.RoundTrip(request)
+
+	t.metrics.apiDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	t.metrics.apiCalls.WithLabelValues(method, status).Inc()
+
+	return response, err
+}
+
+// instrumentClient returns a copy of client whose requests are recorded as calls to the Web API methods under apiURL
+func (m *metrics) instrumentClient(client *http.Client, apiURL string) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	instrumented := *client
+	instrumented.Transport = &metricsTransport{next: transport, metrics: m, apiURL: apiURL}
+	return &instrumented
+}
+
+func (b *Bot) wireMetricsEndpoint(engine *gin.Engine) {
+	if b.metricsPath == "" {
+		return
+	}
+	b.logger().Info("Wired metrics", Fields{"path": b.metricsPath})
+	engine.GET(b.metricsPath, gin.WrapH(promhttp.HandlerFor(b.metricsGatherer, promhttp.HandlerOpts{})))
+}
@@ -0,0 +1,92 @@
+package slackbot
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsCountsDispatches(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	bot := NewBot("token", "secret", OptionMetrics(registry))
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Ftest"))
+		bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(bot.metrics.dispatches.WithLabelValues(dispatchKindCommand, "test", "")))
+	assert.Equal(t, 1, testutil.CollectAndCount(bot.metrics.dispatchDuration))
+}
+
+func TestMetricsCountsVerificationFailuresAndPanics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	bot := NewBot("token", "secret", OptionMetrics(registry))
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		panic("test")
+	})
+
+	request := newSignedRequest(t, "wrong", "/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Ftest"))
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(t, float64(1), testutil.ToFloat64(bot.metrics.verificationFailures))
+
+	request = newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Ftest"))
+	response := httptest.NewRecorder()
+	bot.Handler().ServeHTTP(response, request)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, float64(1), testutil.ToFloat64(bot.metrics.panics.WithLabelValues("/slack/commands")))
+}
+
+func TestMetricsCountsApiCalls(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	bot := server.newBot(OptionMetrics(registry))
+
+	_, err := bot.Api().AuthTest()
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(bot.metrics.apiCalls.WithLabelValues("auth.test", "200")))
+}
+
+func TestMetricsCountsResponseURLPosts(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	bot := server.newBot(OptionMetrics(registry))
+
+	assert.NoError(t, bot.Respond(server.responseURL()+"/T1/1/secret1", &slack.Msg{Text: "one"}))
+	assert.NoError(t, bot.Respond(server.responseURL()+"/T1/2/secret2", &slack.Msg{Text: "two"}))
+
+	// response URLs share one label rather than adding one per URL
+	assert.Equal(t, 1, testutil.CollectAndCount(bot.metrics.apiCalls))
+	assert.Equal(t, 1, testutil.CollectAndCount(bot.metrics.apiDuration))
+	assert.Equal(t, float64(2), testutil.ToFloat64(bot.metrics.apiCalls.WithLabelValues("response_url", "200")))
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	bot := NewBot("token", "secret", OptionMetrics(registry), OptionMetricsEndpoint("/metrics", registry))
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+
+	handler := bot.Handler()
+	request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", []byte("command=%2Ftest"))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `slackbot_dispatches_total{callback_id="",kind="command",name="test"} 1`)
+}
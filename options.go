@@ -0,0 +1,139 @@
+package slackbot
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/slack-go/slack"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// Option configures optional Bot behavior when passed to NewBot
+type Option func(b *Bot)
+
+// Use a different Slack Web API base URL for clients returned by Bot.Api, such as a local mock server.
+// The URL must include the trailing slash, e.g. "http://localhost:8080/api/".
+func OptionAPIURL(url string) Option {
+	return func(b *Bot) {
+		b.apiURL = url
+	}
+}
+
+// Use a custom *http.Client for all outbound requests to Slack, e.g. to add a proxy,
+// instrument the transport or tune connection reuse
+func OptionHTTPClient(client *http.Client) Option {
+	return func(b *Bot) {
+		b.httpClient = client
+	}
+}
+
+// Pass additional slack.Options to clients returned by Bot.Api, e.g. slack.OptionDebug.
+// They are applied after OptionAPIURL and OptionHTTPClient and may override them.
+func OptionSlackOptions(options ...slack.Option) Option {
+	return func(b *Bot) {
+		b.slackOptions = append(b.slackOptions, options...)
+	}
+}
+
+// Record sanitized copies of incoming requests and their responses, e.g. with a FileRecorder,
+// so they may later be replayed with Bot.Replay
+func OptionRecorder(recorder Recorder) Option {
+	return func(b *Bot) {
+		b.recorder = recorder
+	}
+}
+
+// Collect Prometheus metrics about dispatched commands, events and interactions, verification failures,
+// panics and outbound Web API calls, registering the collectors with registerer
+func OptionMetrics(registerer prometheus.Registerer) Option {
+	return func(b *Bot) {
+		b.metrics = newMetrics(registerer)
+	}
+}
+
+// Serve metrics from gatherer at path, e.g. "/metrics", outside of the verified /slack routes
+func OptionMetricsEndpoint(path string, gatherer prometheus.Gatherer) Option {
+	return func(b *Bot) {
+		b.metricsPath = path
+		b.metricsGatherer = gatherer
+	}
+}
+
+// Trace dispatched requests with OpenTelemetry. Callbacks receive the span context through
+// container Context methods and the ...Context registration variants, and Web API calls made
+// with that context through Bot.Api are recorded as child spans.
+func OptionTracerProvider(provider trace.TracerProvider) Option {
+	return func(b *Bot) {
+		b.tracer = provider.Tracer(tracerName)
+		b.tracing = true
+	}
+}
+
+// Use a structured Logger for all framework logs, e.g. NewZapLogger, NewSlogLogger or NopLogger
+func OptionLogger(logger Logger) Option {
+	return func(b *Bot) {
+		b.log = logger
+	}
+}
+
+// Serve liveness checks at /healthz and readiness checks at /readyz. The bot is ready once
+// its token passes auth.test and until it begins shutting down.
+func OptionHealthEndpoints() Option {
+	return func(b *Bot) {
+		b.healthEndpoints = true
+	}
+}
+
+// Serve a JSON description of registered callbacks, queue depths and uptime at /debug/slackbot
+// to requests with an "Authorization: Bearer <token>" header
+func OptionDebugEndpoint(token string) Option {
+	return func(b *Bot) {
+		b.debugToken = token
+	}
+}
+
+// Queue and pace outbound Web API calls according to Slack's rate limit tiers, per token and method or,
+// for chat.postMessage, per channel. A method may burst up to its tier's calls per minute before calls are spaced
+// out, so calls bound to a short lived trigger_id such as views.open are rarely held back.
+// Calls answered with HTTP 429 are retried after Retry-After.
+func OptionRateLimit(options RateLimitOptions) Option {
+	return func(b *Bot) {
+		b.rateLimit = &options
+	}
+}
+
+// Respond to app_rate_limited events by throttling the bot, see Bot.Throttled, and optionally alerting a channel
+func OptionBackpressure(options BackpressureOptions) Option {
+	return func(b *Bot) {
+		b.backpressure = &options
+	}
+}
+
+// Look up the bot token of the team an event came from, for apps installed in several workspaces.
+// The bot token is used when tokens returns an empty string.
+func OptionTeamTokens(tokens func(teamID string) string) Option {
+	return func(b *Bot) {
+		b.teamTokens = tokens
+	}
+}
+
+// Configure timeouts, concurrency and caching of RegisterUnfurler callbacks
+func OptionUnfurl(options UnfurlOptions) Option {
+	return func(b *Bot) {
+		b.unfurl = newUnfurlers(options)
+	}
+}
+
+// Configure leader election and jitter of jobs scheduled with Bot.Schedule
+func OptionScheduler(options SchedulerOptions) Option {
+	return func(b *Bot) {
+		b.scheduler = newScheduler(options)
+	}
+}
+
+// Configure caching and auditing of the policies set with Bot.SetCommandPolicy, Bot.SetActionPolicy and
+// Bot.SetCallbackPolicy
+func OptionACL(options ACLOptions) Option {
+	return func(b *Bot) {
+		b.acl = newACL(options)
+	}
+}
@@ -0,0 +1,66 @@
+package slackbot
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Priorities for SetPriority. Callbacks with a higher priority run first; callbacks run in registration order by default.
+const (
+	PriorityHigh    = 100
+	PriorityDefault = 0
+	PriorityLow     = -100
+)
+
+type handled struct{}
+
+// Returned by an interaction callback to stop later callbacks from running without responding to Slack
+var Handled interface{} = handled{}
+
+type propagationContextKey struct{}
+
+type propagation struct {
+	stopped int32
+}
+
+func withPropagation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, propagationContextKey{}, &propagation{})
+}
+
+// Stop callbacks which would run after the current one for the event or interaction delivered with ctx,
+// such as the Context() of an event container. A response already returned by an interaction callback is still sent.
+func StopPropagation(ctx context.Context) {
+	if p, ok := ctx.Value(propagationContextKey{}).(*propagation); ok {
+		atomic.StoreInt32(&p.stopped, 1)
+	}
+}
+
+func propagationStopped(ctx context.Context) bool {
+	p, ok := ctx.Value(propagationContextKey{}).(*propagation)
+	return ok && atomic.LoadInt32(&p.stopped) == 1
+}
+
+// Set the priority of an event or interaction callback using the Handle returned when it was registered.
+// Callbacks with a higher priority run first, so a maintenance mode or moderation callback registered with
+// PriorityHigh can call StopPropagation or return Handled before feature callbacks run.
+func (b *Bot) SetPriority(handle Handle, priority int) {
+	b.logger().Debug("Set callback priority", Fields{"handle": handle, "priority": priority})
+
+	b.Lock()
+	defer b.Unlock()
+
+	for _, registrations := range b.events {
+		for i := range registrations {
+			if registrations[i].handle == handle {
+				registrations[i].priority = priority
+			}
+		}
+	}
+	for _, registrations := range b.interactives {
+		for i := range registrations {
+			if registrations[i].handle == handle {
+				registrations[i].priority = priority
+			}
+		}
+	}
+}
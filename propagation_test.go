@@ -0,0 +1,119 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestSetPriorityOrdersEventCallbacks(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	var order []string
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {
+		order = append(order, "default")
+	})
+	low := bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {
+		order = append(order, "low")
+	})
+	high := bot.RegisterAnyEvent(func(bot *Bot, c EventContainer) {
+		order = append(order, "high")
+	})
+	bot.SetPriority(low, PriorityLow)
+	bot.SetPriority(high, PriorityHigh)
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.AppMention)).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Equal(t, []string{"high", "default", "low"}, order)
+}
+
+func TestStopPropagationForEvents(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	featureHit := false
+	bot.RegisterAppMentionEvent(func(bot *Bot, c AppMentionEventContainer) {
+		featureHit = true
+	})
+	maintenance := bot.RegisterAnyEvent(func(bot *Bot, c EventContainer) {
+		StopPropagation(c.Context())
+	})
+	bot.SetPriority(maintenance, PriorityHigh)
+	bot.prepareEngine(engine, false)
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEvent(slackevents.AppMention)).
+		Expect().
+		Status(http.StatusOK)
+
+	assert.False(t, featureHit)
+}
+
+func TestHandledStopsInteractionCallbacks(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	featureHit := false
+	bot.RegisterShortcutInteraction("shortcut", func(bot *Bot, interaction slack.InteractionCallback) {
+		featureHit = true
+	})
+	maintenance := bot.RegisterInteractionContext(slack.InteractionTypeShortcut, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		return Handled
+	})
+	bot.SetPriority(maintenance, PriorityHigh)
+	bot.prepareEngine(engine, false)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "shortcut"})
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).NoContent()
+
+	assert.False(t, featureHit)
+}
+
+func TestStopPropagationKeepsInteractionResponse(t *testing.T) {
+	engine := gin.New()
+
+	bot := newBot()
+	featureHit := false
+	bot.RegisterShortcutInteraction("shortcut", func(bot *Bot, interaction slack.InteractionCallback) {
+		featureHit = true
+	})
+	moderation := bot.RegisterInteractionContext(slack.InteractionTypeShortcut, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		StopPropagation(ctx)
+		return &slack.Msg{Text: "down for maintenance"}
+	})
+	bot.SetPriority(moderation, PriorityHigh)
+	bot.prepareEngine(engine, false)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "shortcut"})
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("text", "down for maintenance")
+
+	assert.False(t, featureHit)
+}
+
+func TestStopPropagationWithoutDispatchContext(t *testing.T) {
+	ctx := context.Background()
+	StopPropagation(ctx)
+
+	assert.False(t, propagationStopped(ctx))
+}
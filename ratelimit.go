@@ -0,0 +1,313 @@
+package slackbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Slack Web API rate limit tiers, as calls per minute
+// https://api.slack.com/docs/rate-limits
+const (
+	Tier1 = 1
+	Tier2 = 20
+	Tier3 = 50
+	Tier4 = 100
+)
+
+// Methods which are limited per channel rather than per method, as calls per minute for each channel
+var perChannelRateLimits = map[string]int{
+	"chat.postMessage": 60,
+}
+
+// Rate limit tiers of common Web API methods. Methods not listed are treated as Tier3.
+var MethodRateLimits = map[string]int{
+	"auth.test":             Tier4,
+	"chat.delete":           Tier3,
+	"chat.getPermalink":     Tier4,
+	"chat.postEphemeral":    Tier4,
+	"chat.scheduleMessage":  Tier3,
+	"chat.unfurl":           Tier3,
+	"chat.update":           Tier3,
+	"conversations.create":  Tier2,
+	"conversations.history": Tier3,
+	"conversations.info":    Tier3,
+	"conversations.invite":  Tier3,
+	"conversations.join":    Tier3,
+	"conversations.list":    Tier2,
+	"conversations.members": Tier4,
+	"conversations.open":    Tier3,
+	"conversations.replies": Tier3,
+	"files.upload":          Tier2,
+	"reactions.add":         Tier3,
+	"reactions.get":         Tier4,
+	"reactions.remove":      Tier2,
+	"team.info":             Tier3,
+	"usergroups.list":       Tier2,
+	"usergroups.users.list": Tier2,
+	"users.info":            Tier4,
+	"users.list":            Tier2,
+	"users.lookupByEmail":   Tier3,
+	"views.open":            Tier4,
+	"views.publish":         Tier4,
+	"views.push":            Tier4,
+	"views.update":          Tier4,
+}
+
+// Options for OptionRateLimit
+type RateLimitOptions struct {
+	// Number of times a rate limited call is retried before it is dropped. Defaults to 3.
+	MaxRetries int
+	// Longest a call may wait in its queue before it is dropped. Defaults to one minute.
+	MaxWait time.Duration
+	// Called whenever a call is dropped
+	OnDrop func(drop RateLimitDrop)
+}
+
+// A Web API call which was dropped by the rate limiter
+type RateLimitDrop struct {
+	Method  string
+	Channel string
+	Err     error
+}
+
+// rateLimitClient returns a copy of client whose Web API calls are paced and retried
+func (b *Bot) rateLimitClient(client *http.Client) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	b.rateLimitTransport = newRateLimitTransport(transport, b.webAPIURL(), *b.rateLimit, b.metrics)
+	b.registerQueue("ratelimit", b.rateLimitTransport.depth)
+
+	limited := *client
+	limited.Transport = b.rateLimitTransport
+	return &limited
+}
+
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	apiURL  string
+	options RateLimitOptions
+	metrics *metrics
+
+	// when each queue will have spent its budget at its sustained rate; a call may go ahead while that is no more
+	// than a burst of calls away
+	schedule map[string]time.Time
+	waiting  int32
+	sync.Mutex
+
+	// sleep waits for d or until ctx is done; replaceable for tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimitTransport(next http.RoundTripper, apiURL string, options RateLimitOptions, metrics *metrics) *rateLimitTransport {
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+	if options.MaxWait == 0 {
+		options.MaxWait = time.Minute
+	}
+
+	return &rateLimitTransport{
+		next:     next,
+		apiURL:   apiURL,
+		options:  options,
+		metrics:  metrics,
+		schedule: make(map[string]time.Time),
+		sleep:    sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Number of calls currently waiting for their queue
+func (t *rateLimitTransport) depth() int {
+	return int(atomic.LoadInt32(&t.waiting))
+}
+
+// reserve books the next slot on a queue and returns how long to wait for it,
+// or false without booking if the wait would exceed MaxWait
+func (t *rateLimitTransport) reserve(queue string, limit rateLimit) (time.Duration, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	at := t.schedule[queue]
+	if at.Before(now) {
+		at = now
+	}
+	delay := at.Add(-limit.tolerance()).Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > t.options.MaxWait {
+		return delay, false
+	}
+
+	t.schedule[queue] = at.Add(limit.interval)
+	return delay, true
+}
+
+// pause holds back a queue until Slack's Retry-After has passed, and allows no burst right after it
+func (t *rateLimitTransport) pause(queue string, limit rateLimit, retryAfter time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	if until := time.Now().Add(retryAfter + limit.tolerance()); until.After(t.schedule[queue]) {
+		t.schedule[queue] = until
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(request.URL.String(), t.apiURL) {
+		return t.next.RoundTrip(request)
+	}
+
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		_ = request.Body.Close()
+	}
+
+	method := path.Base(request.URL.Path)
+	channel := requestChannel(request, body)
+	queue, limit := rateLimitQueue(requestToken(request, body), method, channel)
+
+	for attempt := 0; ; attempt++ {
+		delay, ok := t.reserve(queue, limit)
+		if !ok {
+			return nil, t.drop(method, channel, ErrRateLimitDropped)
+		}
+
+		atomic.AddInt32(&t.waiting, 1)
+		err := t.sleep(request.Context(), delay)
+		atomic.AddInt32(&t.waiting, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		attemptRequest := request.Clone(request.Context())
+		attemptRequest.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		response, err := t.next.RoundTrip(attemptRequest)
+		if err != nil || response.StatusCode != http.StatusTooManyRequests {
+			return response, err
+		}
+
+		retryAfter := time.Duration(1<<uint(attempt)) * time.Second
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		t.pause(queue, limit, retryAfter)
+
+		if attempt >= t.options.MaxRetries {
+			_ = t.drop(method, channel, &slack.RateLimitedError{RetryAfter: retryAfter})
+			return response, nil
+		}
+		_ = response.Body.Close()
+
+		if t.metrics != nil {
+			t.metrics.rateLimitRetries.WithLabelValues(method).Inc()
+		}
+	}
+}
+
+func (t *rateLimitTransport) drop(method string, channel string, err error) error {
+	if t.metrics != nil {
+		t.metrics.rateLimitDrops.WithLabelValues(method).Inc()
+	}
+	if t.options.OnDrop != nil {
+		t.options.OnDrop(RateLimitDrop{Method: method, Channel: channel, Err: err})
+	}
+	return err
+}
+
+// The pace of a queue: calls are spaced interval apart once burst calls have gone ahead without waiting
+type rateLimit struct {
+	interval time.Duration
+	burst    int
+}
+
+// tolerance is how far ahead of now a queue may have booked calls and still let a call go ahead
+func (l rateLimit) tolerance() time.Duration {
+	return time.Duration(l.burst-1) * l.interval
+}
+
+// rateLimitQueue returns the queue a call waits in and the pace of that queue.
+// Slack limits each workspace separately, so every token has its own queues.
+// Per channel limits are per second, so they allow no burst.
+func rateLimitQueue(token string, method string, channel string) (string, rateLimit) {
+	if perMinute, exists := perChannelRateLimits[method]; exists && channel != "" {
+		return token + "/" + method + ":" + channel, rateLimit{interval: time.Minute / time.Duration(perMinute), burst: 1}
+	}
+
+	perMinute, exists := MethodRateLimits[method]
+	if !exists {
+		perMinute = Tier3
+	}
+	return token + "/" + method, rateLimit{interval: time.Minute / time.Duration(perMinute), burst: perMinute}
+}
+
+// requestToken reads the token a Web API call is made with, from its Authorization header or token argument
+func requestToken(request *http.Request, body []byte) string {
+	if authorization := request.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimPrefix(authorization, "Bearer ")
+	}
+	if token := request.URL.Query().Get("token"); token != "" {
+		return token
+	}
+	if strings.HasPrefix(request.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	return values.Get("token")
+}
+
+// requestChannel reads the channel argument of a form or JSON encoded Web API call
+func requestChannel(request *http.Request, body []byte) string {
+	if strings.HasPrefix(request.Header.Get("Content-Type"), "application/json") {
+		var args struct {
+			Channel string `json:"channel"`
+		}
+		_ = json.Unmarshal(body, &args)
+		return args.Channel
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	return values.Get("channel")
+}
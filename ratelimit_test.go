@@ -0,0 +1,204 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type sleepRecorder struct {
+	sleeps []time.Duration
+	sync.Mutex
+}
+
+func (r *sleepRecorder) sleep(ctx context.Context, d time.Duration) error {
+	r.Lock()
+	defer r.Unlock()
+	r.sleeps = append(r.sleeps, d)
+	return nil
+}
+
+// newRateLimitedSlack answers the first limited calls with HTTP 429
+func newRateLimitedSlack(limited int32, hits *int32) *fakeSlack {
+	server := newFakeSlack()
+	limit := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(hits, 1) <= limited {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.1"}`))
+	}
+	for _, method := range []string{"chat.postMessage", "users.list", "response"} {
+		server.setHandler(method, limit)
+	}
+	return server
+}
+
+func newRateLimitedBot(server *fakeSlack, options RateLimitOptions) (*Bot, *sleepRecorder) {
+	bot := server.newBot(OptionRateLimit(options))
+	recorder := &sleepRecorder{}
+	bot.rateLimitTransport.sleep = recorder.sleep
+	return bot, recorder
+}
+
+func TestRateLimitRetriesAfterRetryAfter(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(1, &hits)
+	defer server.Close()
+
+	bot, recorder := newRateLimitedBot(server, RateLimitOptions{})
+
+	_, _, err := bot.Api().PostMessage("C1", slack.MsgOptionText("hello", false))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), hits)
+	assert.Equal(t, 2, len(recorder.sleeps))
+	assert.Equal(t, time.Duration(0), recorder.sleeps[0])
+	assert.InDelta(t, float64(7*time.Second), float64(recorder.sleeps[1]), float64(100*time.Millisecond))
+}
+
+func TestRateLimitDropsAfterMaxRetries(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(10, &hits)
+	defer server.Close()
+
+	var drops []RateLimitDrop
+	bot, _ := newRateLimitedBot(server, RateLimitOptions{
+		MaxRetries: 2,
+		MaxWait:    time.Hour,
+		OnDrop: func(drop RateLimitDrop) {
+			drops = append(drops, drop)
+		},
+	})
+
+	_, _, err := bot.Api().PostMessage("C1", slack.MsgOptionText("hello", false))
+
+	assert.IsType(t, &slack.RateLimitedError{}, err)
+	assert.Equal(t, int32(3), hits)
+	assert.Equal(t, 1, len(drops))
+	assert.Equal(t, "chat.postMessage", drops[0].Method)
+	assert.Equal(t, "C1", drops[0].Channel)
+}
+
+func TestRateLimitDropsWhenQueueIsTooLong(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(0, &hits)
+	defer server.Close()
+
+	dropped := false
+	bot, _ := newRateLimitedBot(server, RateLimitOptions{
+		MaxWait: 30 * time.Second,
+		OnDrop: func(drop RateLimitDrop) {
+			dropped = true
+		},
+	})
+
+	// users.list is Tier2, so 20 calls go ahead at once and the next are spaced three seconds apart
+	for i := 0; i < 30; i++ {
+		_, err := bot.Api().GetUsersPaginated().Next(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.False(t, dropped)
+
+	_, err := bot.Api().GetUsersPaginated().Next(context.Background())
+	assert.Error(t, err)
+	assert.True(t, dropped)
+	assert.Equal(t, int32(30), hits)
+}
+
+func TestRateLimitAllowsBursts(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(0, &hits)
+	defer server.Close()
+
+	bot, recorder := newRateLimitedBot(server, RateLimitOptions{})
+
+	for i := 0; i < 21; i++ {
+		_, err := bot.Api().GetUsersPaginated().Next(context.Background())
+		assert.NoError(t, err)
+	}
+
+	if assert.Equal(t, 21, len(recorder.sleeps)) {
+		for _, sleep := range recorder.sleeps[:20] {
+			assert.Equal(t, time.Duration(0), sleep)
+		}
+		assert.InDelta(t, float64(3*time.Second), float64(recorder.sleeps[20]), float64(100*time.Millisecond))
+	}
+}
+
+func TestRateLimitQueues(t *testing.T) {
+	queue, limit := rateLimitQueue("xoxb-1", "chat.postMessage", "C1")
+	assert.Equal(t, "xoxb-1/chat.postMessage:C1", queue)
+	assert.Equal(t, rateLimit{interval: time.Second, burst: 1}, limit)
+
+	queue, limit = rateLimitQueue("xoxb-1", "users.list", "")
+	assert.Equal(t, "xoxb-1/users.list", queue)
+	assert.Equal(t, rateLimit{interval: 3 * time.Second, burst: Tier2}, limit)
+
+	queue, limit = rateLimitQueue("xoxb-2", "unknown.method", "C1")
+	assert.Equal(t, "xoxb-2/unknown.method", queue)
+	assert.Equal(t, rateLimit{interval: 1200 * time.Millisecond, burst: Tier3}, limit)
+}
+
+func TestRateLimitPacesPerChannel(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(0, &hits)
+	defer server.Close()
+
+	bot, recorder := newRateLimitedBot(server, RateLimitOptions{})
+
+	for _, channel := range []string{"C1", "C2", "C1"} {
+		_, _, err := bot.Api().PostMessage(channel, slack.MsgOptionText("hello", false))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, time.Duration(0), recorder.sleeps[0])
+	assert.Equal(t, time.Duration(0), recorder.sleeps[1])
+	assert.InDelta(t, float64(time.Second), float64(recorder.sleeps[2]), float64(100*time.Millisecond))
+}
+
+func TestRateLimitPacesPerTeam(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(0, &hits)
+	defer server.Close()
+
+	bot := server.newBot(OptionRateLimit(RateLimitOptions{}), OptionTeamTokens(func(teamID string) string {
+		return "token-" + teamID
+	}))
+	recorder := &sleepRecorder{}
+	bot.rateLimitTransport.sleep = recorder.sleep
+
+	// messages to one channel of one team are spaced a second apart
+	for _, teamID := range []string{"T1", "T2", "T1", "T2"} {
+		_, _, err := bot.ApiForTeam(teamID).PostMessage("C1", slack.MsgOptionText("hello", false))
+		assert.NoError(t, err)
+	}
+
+	if assert.Equal(t, 4, len(recorder.sleeps)) {
+		assert.Equal(t, time.Duration(0), recorder.sleeps[0])
+		assert.Equal(t, time.Duration(0), recorder.sleeps[1])
+		assert.InDelta(t, float64(time.Second), float64(recorder.sleeps[2]), float64(100*time.Millisecond))
+		assert.InDelta(t, float64(time.Second), float64(recorder.sleeps[3]), float64(100*time.Millisecond))
+	}
+}
+
+func TestRateLimitIgnoresNonApiRequests(t *testing.T) {
+	var hits int32
+	server := newRateLimitedSlack(1, &hits)
+	defer server.Close()
+
+	bot, recorder := newRateLimitedBot(server, RateLimitOptions{})
+
+	err := bot.Respond(server.responseURL(), &slack.Msg{Text: "hello"})
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), hits)
+	assert.Empty(t, recorder.sleeps)
+	assert.Contains(t, bot.DebugInfo().Queues, "ratelimit")
+}
@@ -0,0 +1,257 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A reaction trigger firing on, or being undone for, a message
+type ReactionTriggerEvent struct {
+	TeamID    string
+	ChannelID string
+	// Emoji name of the trigger, without colons
+	Emoji string
+	// User whose reaction fired or undid the trigger
+	UserID string
+	// Number of reactions to the message with the emoji or its skin tone variants after the reaction
+	Count int
+	// Users reacting to the message with the emoji after the reaction, which Slack truncates on popular messages
+	Users []string
+	// The reacted message, including its text and reactions
+	Message slack.Message
+	// Permalink of the message, empty if it could not be resolved
+	Permalink string
+	ctx       context.Context
+	bot       *Bot
+}
+
+// Get the context of the request which delivered the reaction. The request has been answered by the time the callbacks
+// run, so the context keeps its values but is never cancelled.
+func (e ReactionTriggerEvent) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+func (e ReactionTriggerEvent) ref() messageRef {
+	return messageRef{
+		bot:      e.bot,
+		ctx:      e.Context(),
+		teamID:   e.TeamID,
+		channel:  e.ChannelID,
+		user:     e.Message.User,
+		ts:       e.Message.Timestamp,
+		threadTS: e.Message.ThreadTimestamp,
+	}
+}
+
+// Reply in the thread of the reacted message, starting one if there is none. Returns the timestamp of the reply.
+func (e ReactionTriggerEvent) ReplyInThread(text string, options ...slack.MsgOption) (string, error) {
+	return e.ref().replyInThread(text, options)
+}
+
+// React to the reacted message with an emoji name, with or without surrounding colons
+func (e ReactionTriggerEvent) React(emoji string) error {
+	return e.ref().react(emoji)
+}
+
+type ReactionTriggerCallback = func(bot *Bot, event ReactionTriggerEvent)
+
+// Callbacks of a reaction trigger
+type ReactionTrigger struct {
+	// Number of reactions with the emoji or its skin tone variants needed before the trigger fires. Defaults to 1.
+	Threshold int
+	// Called once per message when the number of reactions reaches Threshold
+	OnTrigger ReactionTriggerCallback
+	// Called, if set, when reactions are removed from a message the trigger fired on until there are fewer than
+	// Threshold. The trigger may then fire again for that message.
+	OnUndo ReactionTriggerCallback
+	// How long a message the trigger fired on is remembered, after which the trigger may fire again for it and is no
+	// longer undone for it. Defaults to 24 hours.
+	Memory time.Duration
+}
+
+type reactionTrigger struct {
+	ReactionTrigger
+	emoji string
+	// when the messages the trigger fired on are forgotten, by channel and timestamp
+	fired map[string]time.Time
+	sync.Mutex
+}
+
+// firedLocked reports whether the trigger fired on a message, forgetting the messages it fired on too long ago, and
+// must be called with the lock held
+func (t *reactionTrigger) firedLocked(key string, now time.Time) bool {
+	for k, expires := range t.fired {
+		if !now.Before(expires) {
+			delete(t.fired, k)
+		}
+	}
+	_, fired := t.fired[key]
+	return fired
+}
+
+// Register a trigger for an emoji reaction on messages, with or without surrounding colons. Skin tone variants of the
+// emoji count as the emoji. The callbacks run in the background after the event is answered. The reacted message and
+// its permalink are resolved before they run, which needs the channels:history scope, or the matching scope of the
+// conversation type.
+func (b *Bot) RegisterReactionTrigger(emoji string, trigger ReactionTrigger) Handle {
+	if trigger.Threshold < 1 {
+		trigger.Threshold = 1
+	}
+	if trigger.Memory <= 0 {
+		trigger.Memory = 24 * time.Hour
+	}
+	t := &reactionTrigger{ReactionTrigger: trigger, emoji: reactionName(emoji), fired: make(map[string]time.Time)}
+
+	return b.registerEventGroup(map[string]eventCallback{
+		slackevents.ReactionAdded: func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+			e, ok := event.InnerEvent.Data.(*slackevents.ReactionAddedEvent)
+			if ok && e.Item.Type == "message" && reactionName(e.Reaction) == t.emoji {
+				b.goDetached(ctx, func(ctx context.Context) {
+					b.reactionChanged(ctx, t, event.TeamID, e.User, e.Item, true)
+				})
+			}
+		},
+		slackevents.ReactionRemoved: func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+			e, ok := event.InnerEvent.Data.(*slackevents.ReactionRemovedEvent)
+			if ok && e.Item.Type == "message" && reactionName(e.Reaction) == t.emoji {
+				b.goDetached(ctx, func(ctx context.Context) {
+					b.reactionChanged(ctx, t, event.TeamID, e.User, e.Item, false)
+				})
+			}
+		},
+	})
+}
+
+func (b *Bot) reactionChanged(ctx context.Context, t *reactionTrigger, teamID string, userID string, item slackevents.Item, added bool) {
+	key := item.Channel + "/" + item.Timestamp
+	if !added {
+		t.Lock()
+		fired := t.firedLocked(key, time.Now())
+		t.Unlock()
+		if !fired {
+			return
+		}
+	}
+
+	api := b.ApiForTeam(teamID)
+	message, err := reactedMessage(ctx, api, item.Channel, item.Timestamp)
+	if err != nil {
+		b.logger().Error("Failed to resolve reacted message", Fields{"team_id": teamID, "channel_id": item.Channel, "ts": item.Timestamp, "error": err})
+		return
+	}
+	count, users := countReactions(message, t.emoji)
+
+	// the reactions of the message decide, so duplicate or out of order events fire at most once
+	t.Lock()
+	now := time.Now()
+	fired := t.firedLocked(key, now)
+	reached := count >= t.Threshold
+	var callback ReactionTriggerCallback
+	switch {
+	case added && reached && !fired:
+		t.fired[key] = now.Add(t.Memory)
+		callback = t.OnTrigger
+	case !added && !reached && fired:
+		delete(t.fired, key)
+		callback = t.OnUndo
+	}
+	t.Unlock()
+	if callback == nil {
+		return
+	}
+
+	permalink, err := api.GetPermalinkContext(ctx, &slack.PermalinkParameters{Channel: item.Channel, Ts: item.Timestamp})
+	if err != nil {
+		b.logger().Warn("Failed to resolve permalink", Fields{"team_id": teamID, "channel_id": item.Channel, "ts": item.Timestamp, "error": err})
+	}
+	callback(b, ReactionTriggerEvent{
+		TeamID:    teamID,
+		ChannelID: item.Channel,
+		Emoji:     t.emoji,
+		UserID:    userID,
+		Count:     count,
+		Users:     users,
+		Message:   message,
+		Permalink: permalink,
+		ctx:       ctx,
+		bot:       b,
+	})
+}
+
+// reactedMessage fetches a message by its timestamp, whether it was posted in the channel or in a thread. Replies
+// only posted in their thread are not in the history of the channel, and conversations.replies always starts with the
+// parent of the thread, so the messages returned are matched on their timestamp.
+func reactedMessage(ctx context.Context, api *slack.Client, channel string, ts string) (slack.Message, error) {
+	history, err := api.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Latest:    ts,
+		Oldest:    ts,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		return slack.Message{}, err
+	}
+	if message, ok := messageWithTimestamp(history.Messages, ts); ok {
+		return message, nil
+	}
+
+	replies, _, _, err := api.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channel,
+		Timestamp: ts,
+		Latest:    ts,
+		Oldest:    ts,
+		Inclusive: true,
+	})
+	if err != nil {
+		return slack.Message{}, err
+	}
+	if message, ok := messageWithTimestamp(replies, ts); ok {
+		return message, nil
+	}
+	return slack.Message{}, ErrMessageNotFound
+}
+
+func messageWithTimestamp(messages []slack.Message, ts string) (slack.Message, bool) {
+	for _, message := range messages {
+		if message.Timestamp == ts {
+			return message, true
+		}
+	}
+	return slack.Message{}, false
+}
+
+// countReactions returns the number of reactions to a message with an emoji or its skin tone variants, and the distinct
+// users listed for them. Slack truncates the users of popular reactions, so only the number is complete.
+func countReactions(message slack.Message, emoji string) (int, []string) {
+	count := 0
+	var users []string
+	for _, reaction := range message.Reactions {
+		if reactionName(reaction.Name) != emoji {
+			continue
+		}
+		count += reaction.Count
+		for _, user := range reaction.Users {
+			if !contains(users, user) {
+				users = append(users, user)
+			}
+		}
+	}
+	return count, users
+}
+
+// reactionName strips the colons and skin tone of an emoji, so "+1::skin-tone-2" and ":+1:" are both "+1"
+func reactionName(emoji string) string {
+	emoji = strings.Trim(emoji, ":")
+	if i := strings.Index(emoji, "::skin-tone-"); i >= 0 {
+		emoji = emoji[:i]
+	}
+	return emoji
+}
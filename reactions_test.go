@@ -0,0 +1,215 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newReactionsSlack serves the message at 1.1 in C1 with the reactions currently set, and its reply at 1.2 which is
+// only in the thread
+func newReactionsSlack(reactions *[]slack.ItemReaction) *fakeSlack {
+	server := newFakeSlack()
+	parent := func() slack.Message {
+		return slack.Message{Msg: slack.Msg{Text: "it is broken", User: "U0", Timestamp: "1.1", ThreadTimestamp: "1.1", Reactions: *reactions}}
+	}
+	server.setResponseFunc("conversations.history", func(call apiCall) interface{} {
+		var messages []slack.Message
+		if call.form.Get("latest") == "1.1" {
+			messages = append(messages, parent())
+		}
+		return map[string]interface{}{"ok": true, "messages": messages}
+	})
+	server.setResponseFunc("conversations.replies", func(call apiCall) interface{} {
+		reply := slack.Message{Msg: slack.Msg{Text: "still broken", User: "U1", Timestamp: "1.2", ThreadTimestamp: "1.1"}}
+		return map[string]interface{}{"ok": true, "messages": []slack.Message{parent(), reply}}
+	})
+	server.setResponse("chat.getPermalink", map[string]interface{}{"ok": true, "channel": "C1", "permalink": "https://example.slack.com/archives/C1/p11"})
+	return server
+}
+
+// postReaction posts a reaction event on the message at 1.1 and waits for the triggers it started
+func postReaction(t *testing.T, engine *gin.Engine, bot *Bot, eventType string, user string, reaction string) {
+	item := slackevents.Item{Type: "message", Channel: "C1", Timestamp: "1.1"}
+	var event interface{} = slackevents.ReactionAddedEvent{User: user, Reaction: reaction, Item: item}
+	if eventType == slackevents.ReactionRemoved {
+		event = slackevents.ReactionRemovedEvent{User: user, Reaction: reaction, Item: item}
+	}
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(newFakeEventWithData(eventType, event)).
+		Expect().
+		Status(http.StatusOK)
+	bot.detached.Wait()
+}
+
+func TestRegisterReactionTrigger(t *testing.T) {
+	reactions := []slack.ItemReaction{{Name: "ticket", Count: 1, Users: []string{"U1"}}}
+	server := newReactionsSlack(&reactions)
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	var triggered []ReactionTriggerEvent
+	bot.RegisterReactionTrigger(":ticket:", ReactionTrigger{
+		OnTrigger: func(bot *Bot, event ReactionTriggerEvent) {
+			triggered = append(triggered, event)
+		},
+	})
+	bot.prepareEngine(engine, false)
+
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U1", "eyes")
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U1", "ticket")
+	// a second reaction on the same message does not file another ticket
+	reactions[0] = slack.ItemReaction{Name: "ticket", Count: 2, Users: []string{"U1", "U2"}}
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U2", "ticket")
+
+	if assert.Equal(t, 1, len(triggered)) {
+		assert.Equal(t, "C1", triggered[0].ChannelID)
+		assert.Equal(t, "ticket", triggered[0].Emoji)
+		assert.Equal(t, "U1", triggered[0].UserID)
+		assert.Equal(t, "it is broken", triggered[0].Message.Text)
+		assert.Equal(t, "https://example.slack.com/archives/C1/p11", triggered[0].Permalink)
+	}
+}
+
+func TestReactionTriggerThresholdAndUndo(t *testing.T) {
+	var reactions []slack.ItemReaction
+	server := newReactionsSlack(&reactions)
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	var events []string
+	bot.RegisterReactionTrigger("+1", ReactionTrigger{
+		Threshold: 2,
+		OnTrigger: func(bot *Bot, event ReactionTriggerEvent) {
+			events = append(events, "trigger "+strings.Join(event.Users, ","))
+		},
+		OnUndo: func(bot *Bot, event ReactionTriggerEvent) {
+			events = append(events, "undo "+event.UserID)
+		},
+	})
+	bot.prepareEngine(engine, false)
+
+	reactions = []slack.ItemReaction{{Name: "+1", Count: 1, Users: []string{"U1"}}}
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U1", "+1")
+	assert.Empty(t, events)
+
+	reactions = append(reactions, slack.ItemReaction{Name: "+1::skin-tone-3", Count: 1, Users: []string{"U2"}})
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U2", "+1::skin-tone-3")
+	assert.Equal(t, []string{"trigger U1,U2"}, events)
+
+	reactions = reactions[:1]
+	postReaction(t, engine, bot, slackevents.ReactionRemoved, "U2", "+1::skin-tone-3")
+	assert.Equal(t, []string{"trigger U1,U2", "undo U2"}, events)
+
+	// once undone the trigger may fire again
+	reactions = append(reactions, slack.ItemReaction{Name: "+1", Count: 1, Users: []string{"U3"}})
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U3", "+1")
+	assert.Equal(t, []string{"trigger U1,U2", "undo U2", "trigger U1,U3"}, events)
+}
+
+func TestReactionTriggerCountsTruncatedUsers(t *testing.T) {
+	// Slack lists only some of the users of a popular reaction
+	reactions := []slack.ItemReaction{
+		{Name: "fire", Count: 40, Users: []string{"U1", "U2"}},
+		{Name: "fire::skin-tone-2", Count: 10, Users: []string{"U3"}},
+	}
+	server := newReactionsSlack(&reactions)
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	var triggered []ReactionTriggerEvent
+	bot.RegisterReactionTrigger("fire", ReactionTrigger{
+		Threshold: 50,
+		OnTrigger: func(bot *Bot, event ReactionTriggerEvent) {
+			triggered = append(triggered, event)
+		},
+	})
+	bot.prepareEngine(engine, false)
+
+	postReaction(t, engine, bot, slackevents.ReactionAdded, "U3", "fire::skin-tone-2")
+
+	if assert.Equal(t, 1, len(triggered)) {
+		assert.Equal(t, 50, triggered[0].Count)
+		assert.Equal(t, []string{"U1", "U2", "U3"}, triggered[0].Users)
+	}
+}
+
+func TestReactedMessage(t *testing.T) {
+	var reactions []slack.ItemReaction
+	server := newReactionsSlack(&reactions)
+	defer server.Close()
+
+	api := server.newBot().ApiForTeam("T1")
+	message, err := reactedMessage(context.Background(), api, "C1", "1.1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "it is broken", message.Text)
+	}
+	assert.Empty(t, server.callsTo("conversations.replies"))
+
+	// a reply only in its thread is not the parent conversations.replies starts with
+	message, err = reactedMessage(context.Background(), api, "C1", "1.2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "still broken", message.Text)
+	}
+
+	_, err = reactedMessage(context.Background(), api, "C1", "1.3")
+	assert.Equal(t, ErrMessageNotFound, err)
+}
+
+func TestReactionTriggerMemory(t *testing.T) {
+	reactions := []slack.ItemReaction{{Name: "ticket", Count: 1, Users: []string{"U1"}}}
+	server := newReactionsSlack(&reactions)
+	defer server.Close()
+
+	bot := server.newBot()
+	triggered := 0
+	trigger := &reactionTrigger{
+		ReactionTrigger: ReactionTrigger{Threshold: 1, Memory: time.Hour, OnTrigger: func(bot *Bot, event ReactionTriggerEvent) {
+			triggered++
+		}},
+		emoji: "ticket",
+		fired: map[string]time.Time{"C2/1.1": time.Now().Add(-time.Second)},
+	}
+	item := slackevents.Item{Type: "message", Channel: "C1", Timestamp: "1.1"}
+
+	bot.reactionChanged(context.Background(), trigger, "T1", "U1", item, true)
+	bot.reactionChanged(context.Background(), trigger, "T1", "U1", item, true)
+	assert.Equal(t, 1, triggered)
+	// messages fired on too long ago are forgotten
+	if assert.Equal(t, 1, len(trigger.fired)) {
+		assert.True(t, trigger.fired["C1/1.1"].After(time.Now().Add(59*time.Minute)))
+	}
+
+	trigger.fired["C1/1.1"] = time.Now()
+	bot.reactionChanged(context.Background(), trigger, "T1", "U1", item, true)
+	assert.Equal(t, 2, triggered)
+}
+
+func TestUnregisterReactionTrigger(t *testing.T) {
+	bot := newBot()
+
+	handle := bot.RegisterReactionTrigger("ticket", ReactionTrigger{})
+	assert.Equal(t, 1, len(bot.events[slackevents.ReactionAdded]))
+	assert.Equal(t, 1, len(bot.events[slackevents.ReactionRemoved]))
+
+	bot.UnregisterEvent(handle)
+	assert.Equal(t, 0, len(bot.events[slackevents.ReactionAdded]))
+	assert.Equal(t, 0, len(bot.events[slackevents.ReactionRemoved]))
+}
+
+func TestReactionName(t *testing.T) {
+	assert.Equal(t, "ticket", reactionName(":ticket:"))
+	assert.Equal(t, "+1", reactionName("+1::skin-tone-2"))
+	assert.Equal(t, "+1", reactionName(":+1::skin-tone-2:"))
+}
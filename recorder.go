@@ -0,0 +1,302 @@
+package slackbot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const verifiedContextKey = "slackbot.verified"
+const redacted = "REDACTED"
+
+// Headers which are never written to a Recording
+var sensitiveHeaders = []string{"Authorization", "Cookie", "X-Slack-Signature"}
+
+// Body fields which are redacted before being written to a Recording
+var sensitiveFields = map[string]bool{"token": true, "response_url": true}
+
+// A sanitized copy of an incoming request and the bot's response to it
+type Recording struct {
+	Time     time.Time   `json:"time"`
+	Method   string      `json:"method"`
+	Path     string      `json:"path"`
+	Header   http.Header `json:"header"`
+	Body     string      `json:"body"`
+	Verified bool        `json:"verified"`
+	Status   int         `json:"status"`
+	Response string      `json:"response"`
+}
+
+// Recorder stores Recordings of incoming requests
+type Recorder interface {
+	Record(recording Recording) error
+}
+
+// FileRecorder writes Recordings to a JSONL file, rotating it once it grows past a maximum size
+type FileRecorder struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	file *os.File
+	size int64
+
+	sync.Mutex
+}
+
+// Create a FileRecorder appending to path. Once the file would grow past maxBytes it is rotated
+// to path.1, path.2, ... keeping at most maxBackups old files.
+func NewFileRecorder(path string, maxBytes int64, maxBackups int) (*FileRecorder, error) {
+	r := &FileRecorder{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FileRecorder) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write a Recording as a single JSON line
+func (r *FileRecorder) Record(recording Recording) error {
+	line, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.size > 0 && r.size+int64(len(line)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+func (r *FileRecorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups > 0 {
+		for i := r.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+// Close the underlying file
+func (r *FileRecorder) Close() error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.file.Close()
+}
+
+type recordingResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (b *Bot) newRecorderMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		writer := &recordingResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		recording := Recording{
+			Time:     time.Now(),
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.Path,
+			Header:   sanitizeHeader(c.Request.Header),
+			Body:     sanitizeBody(c.ContentType(), body),
+			Verified: c.GetBool(verifiedContextKey),
+			Status:   writer.Status(),
+			Response: writer.body.String(),
+		}
+		if err := b.recorder.Record(recording); err != nil {
+			b.logger().Error("Failed to record request", Fields{"error": err, "request_id": c.GetString(requestIDContextKey)})
+		}
+	}
+}
+
+func sanitizeHeader(header http.Header) http.Header {
+	sanitized := header.Clone()
+	for _, name := range sensitiveHeaders {
+		sanitized.Del(name)
+	}
+	return sanitized
+}
+
+func sanitizeBody(contentType string, body []byte) string {
+	if contentType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		for key := range values {
+			if sensitiveFields[key] {
+				values.Set(key, redacted)
+			}
+		}
+		if payload := values.Get("payload"); payload != "" {
+			values.Set("payload", sanitizeJSON([]byte(payload)))
+		}
+		return values.Encode()
+	}
+
+	return sanitizeJSON(body)
+}
+
+func sanitizeJSON(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return string(body)
+	}
+	sanitized, err := json.Marshal(redactJSON(data))
+	if err != nil {
+		return string(body)
+	}
+	return string(sanitized)
+}
+
+func redactJSON(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if _, isString := field.(string); isString && sensitiveFields[key] {
+				value[key] = redacted
+			} else {
+				value[key] = redactJSON(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+	}
+	return data
+}
+
+// Read Recordings written by a FileRecorder
+func ReadRecordings(r io.Reader) ([]Recording, error) {
+	recordings := make([]Recording, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var recording Recording
+		if err := json.Unmarshal([]byte(line), &recording); err != nil {
+			return nil, err
+		}
+		recordings = append(recordings, recording)
+	}
+
+	return recordings, scanner.Err()
+}
+
+// Feed Recordings back through the bot's callbacks in order with signature verification bypassed,
+// to reproduce the bot's behavior for captured requests locally. Returns the response to each recording.
+func (b *Bot) Replay(recordings ...Recording) []*http.Response {
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	b.prepareEngine(engine, false)
+
+	responses := make([]*http.Response, 0, len(recordings))
+	for _, recording := range recordings {
+		request := httptest.NewRequest(recording.Method, recording.Path, strings.NewReader(recording.Body))
+		for name, values := range recording.Header {
+			request.Header[name] = values
+		}
+
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, request)
+		responses = append(responses, recorder.Result())
+	}
+	return responses
+}
+
+// Replay every Recording in a file written by a FileRecorder, in order
+func (b *Bot) ReplayFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	recordings, err := ReadRecordings(file)
+	if err != nil {
+		return err
+	}
+
+	for i, response := range b.Replay(recordings...) {
+		recording := recordings[i]
+		b.logger().Info("Replayed request", Fields{
+			"method":          recording.Method,
+			"path":            recording.Path,
+			"recorded_at":     recording.Time,
+			"status":          response.StatusCode,
+			"recorded_status": recording.Status,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package slackbot
+
+import (
+	"bytes"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type memoryRecorder struct {
+	recordings []Recording
+}
+
+func (r *memoryRecorder) Record(recording Recording) error {
+	r.recordings = append(r.recordings, recording)
+	return nil
+}
+
+func newCommandForm() url.Values {
+	form := url.Values{}
+	form.Set("command", "/test")
+	form.Set("text", "hello")
+	form.Set("token", "verification-token")
+	form.Set("response_url", "https://hooks.slack.com/commands/secret")
+	return form
+}
+
+func TestRecorderRecordsSanitizedRequests(t *testing.T) {
+	recorder := &memoryRecorder{}
+	bot := NewBot("token", "secret", OptionRecorder(recorder))
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return &slack.Msg{Text: command.Text}
+	})
+
+	request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", []byte(newCommandForm().Encode()))
+	response := httptest.NewRecorder()
+	bot.Handler().ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, 1, len(recorder.recordings))
+
+	recording := recorder.recordings[0]
+	assert.True(t, recording.Verified)
+	assert.Equal(t, http.StatusOK, recording.Status)
+	assert.Equal(t, "/slack/commands", recording.Path)
+	assert.Equal(t, "", recording.Header.Get("X-Slack-Signature"))
+	assert.JSONEq(t, `{"text": "hello", "replace_original": false, "delete_original": false, "blocks": null}`, recording.Response)
+
+	body, _ := url.ParseQuery(recording.Body)
+	assert.Equal(t, "hello", body.Get("text"))
+	assert.Equal(t, redacted, body.Get("token"))
+	assert.Equal(t, redacted, body.Get("response_url"))
+}
+
+func TestRecorderRecordsVerificationFailures(t *testing.T) {
+	recorder := &memoryRecorder{}
+	bot := NewBot("token", "secret", OptionRecorder(recorder))
+
+	request := newSignedRequest(t, "wrong", "/slack/commands", "application/x-www-form-urlencoded", []byte(newCommandForm().Encode()))
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, 1, len(recorder.recordings))
+	assert.False(t, recorder.recordings[0].Verified)
+	assert.Equal(t, http.StatusUnauthorized, recorder.recordings[0].Status)
+}
+
+func TestSanitizeJSONRedactsNestedFields(t *testing.T) {
+	sanitized := sanitizeJSON([]byte(`{"token": "abc", "event": {"text": "hi", "token": "def"}, "items": [{"response_url": "https://example.com"}]}`))
+
+	assert.JSONEq(t, `{"token": "REDACTED", "event": {"text": "hi", "token": "REDACTED"}, "items": [{"response_url": "REDACTED"}]}`, sanitized)
+}
+
+func TestReplay(t *testing.T) {
+	recorder := &memoryRecorder{}
+	bot := NewBot("token", "secret", OptionRecorder(recorder))
+	hits := 0
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		hits++
+		return &slack.Msg{Text: command.Text}
+	})
+
+	request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", []byte(newCommandForm().Encode()))
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	responses := bot.Replay(recorder.recordings[0], recorder.recordings[0])
+	if assert.Equal(t, 2, len(responses)) {
+		body, _ := ioutil.ReadAll(responses[1].Body)
+		assert.Equal(t, http.StatusOK, responses[1].StatusCode)
+		assert.Contains(t, string(body), "hello")
+	}
+	assert.Equal(t, 3, hits)
+	assert.Equal(t, 1, len(recorder.recordings))
+}
+
+func TestReplayWiresRoutesOnce(t *testing.T) {
+	logger := &memoryLogger{}
+	bot := NewBot("token", "secret", OptionLogger(logger))
+	recording := Recording{Method: http.MethodPost, Path: "/slack/commands", Body: newCommandForm().Encode()}
+	recording.Header = http.Header{"Content-Type": []string{"application/x-www-form-urlencoded"}}
+
+	bot.Replay(recording, recording, recording)
+
+	wired := 0
+	for _, entry := range logger.entries {
+		if entry.msg == "Wired commands" {
+			wired++
+		}
+	}
+	assert.Equal(t, 1, wired)
+}
+
+func TestFileRecorderRotatesAndReplays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "slackbot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recordings.jsonl")
+	recorder, err := NewFileRecorder(path, 200, 2)
+	assert.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, recorder.Record(Recording{Method: http.MethodPost, Path: "/slack/commands", Header: header, Body: newCommandForm().Encode()}))
+	}
+	assert.NoError(t, recorder.Close())
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		_, err := os.Stat(name)
+		assert.NoError(t, err)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	recordings, err := ReadRecordings(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(recordings))
+	assert.Equal(t, "/slack/commands", recordings[0].Path)
+
+	bot := newBot()
+	hit := false
+	bot.RegisterCommand("test", func(bot *Bot, command slack.SlashCommand) *slack.Msg {
+		hit = true
+		return nil
+	})
+	assert.NoError(t, bot.ReplayFile(path))
+	assert.True(t, hit)
+}
@@ -0,0 +1,544 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/slack-go/slack"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options for RegisterReminders
+type ReminderOptions struct {
+	// Storage persisting reminders. Defaults to a MemoryStorage, which loses reminders when the bot restarts.
+	Storage Storage
+	// Name of the slash command. Defaults to "remind".
+	Command string
+}
+
+type reminder struct {
+	ID        string `json:"id"`
+	TeamID    string `json:"team_id"`
+	CreatorID string `json:"creator_id"`
+	// user or channel the reminder is posted to
+	ChannelID string `json:"channel_id"`
+	Text      string `json:"text"`
+	// when the reminder is due as the creator wrote it, such as "every weekday at 9am"
+	When string `json:"when"`
+	// cron expression of a recurring reminder, empty for a one-off
+	Cron     string    `json:"cron,omitempty"`
+	Timezone string    `json:"timezone"`
+	NextRun  time.Time `json:"next_run"`
+}
+
+type reminders struct {
+	storage Storage
+	command string
+}
+
+const (
+	reminderKeyPrefix      = "slackbot/reminders/"
+	reminderCancelActionID = "slackbot_reminder_cancel"
+)
+
+var errReminderUsage = errors.New("usage")
+
+// Register a slash command creating reminders, such as "/remind me to stretch in 2h" or
+// "/remind #team standup every weekday at 9:30am". The target is "me", "here" for the current channel, a channel or a
+// user; channels and users must be escaped, which is a setting of the slash command. "/remind list" lists the user's
+// reminders with buttons to cancel them.
+//
+// Times are read in the user's timezone, which needs the users:read scope, and may be "in 2h", "at 5pm",
+// "tomorrow", "friday at 10:30", "every day", "every weekday at 9am" or "every monday and thursday at noon".
+// Reminders are posted by a job of the bot's scheduler checking every minute for due reminders. The job lists the
+// reminders of every team from Storage each minute, on the leader only when the scheduler has a Storage and on every
+// replica otherwise, so a Storage holding many reminders should list keys by prefix cheaply.
+func (b *Bot) RegisterReminders(options ReminderOptions) {
+	if options.Storage == nil {
+		options.Storage = NewMemoryStorage()
+	}
+	if options.Command == "" {
+		options.Command = "remind"
+	}
+	r := &reminders{storage: options.Storage, command: commandName(options.Command)}
+
+	b.RegisterCommandContext(r.command, func(ctx context.Context, bot *Bot, command slack.SlashCommand) *slack.Msg {
+		return b.reminderCommand(ctx, r, command)
+	})
+	b.registerDescribedInteractive(slack.InteractionTypeBlockActions, "reminder cancel", BlockActionFilter{ActionID: reminderCancelActionID}.match, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		b.cancelReminder(ctx, r, interaction)
+		return nil
+	})
+	_, _ = b.Schedule("* * * * *", "", func(ctx context.Context, bot *Bot) {
+		b.deliverReminders(ctx, r, time.Now())
+	})
+}
+
+func ephemeral(text string) *slack.Msg {
+	return &slack.Msg{ResponseType: slack.ResponseTypeEphemeral, Text: text}
+}
+
+func (b *Bot) reminderCommand(ctx context.Context, r *reminders, command slack.SlashCommand) *slack.Msg {
+	text := strings.TrimSpace(command.Text)
+	if text == "list" {
+		return b.reminderList(ctx, r, command.TeamID, command.UserID)
+	}
+
+	location := b.userLocation(ctx, command.TeamID, command.UserID)
+	rem, err := parseReminder(text, time.Now().In(location))
+	if err != nil {
+		return ephemeral(fmt.Sprintf("Sorry, I didn't understand that. Try `/%s me to stretch in 2h`, "+
+			"`/%s #team standup every weekday at 9:30am` or `/%s list`.", r.command, r.command, r.command))
+	}
+	switch rem.ChannelID {
+	case "me":
+		rem.ChannelID = command.UserID
+	case "here":
+		rem.ChannelID = command.ChannelID
+	}
+	rem.ID = newRequestID()
+	rem.TeamID = command.TeamID
+	rem.CreatorID = command.UserID
+	rem.Timezone = location.String()
+
+	if err := r.save(ctx, rem); err != nil {
+		b.logger().Error("Failed to save reminder", Fields{"team_id": rem.TeamID, "user_id": rem.CreatorID, "error": err})
+		return ephemeral("Sorry, I couldn't save that reminder.")
+	}
+	b.logger().Debug("Created reminder", Fields{"team_id": rem.TeamID, "user_id": rem.CreatorID, "reminder_id": rem.ID})
+	return ephemeral(fmt.Sprintf("I will remind %s \"%s\" %s, next %s.", reminderTarget(rem, command.UserID), rem.Text, rem.When, slackDate(rem.NextRun)))
+}
+
+// userLocation returns the timezone of a user, or UTC when it is unknown
+func (b *Bot) userLocation(ctx context.Context, teamID string, userID string) *time.Location {
+	user, err := b.ApiForTeam(teamID).GetUserInfoContext(ctx, userID)
+	if err != nil {
+		b.logger().Warn("Failed to get user timezone", Fields{"team_id": teamID, "user_id": userID, "error": err})
+		return time.UTC
+	}
+	location, err := time.LoadLocation(user.TZ)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (b *Bot) reminderList(ctx context.Context, r *reminders, teamID string, userID string) *slack.Msg {
+	list, err := r.list(ctx, teamID)
+	if err != nil {
+		b.logger().Error("Failed to list reminders", Fields{"team_id": teamID, "user_id": userID, "error": err})
+		return ephemeral("Sorry, I couldn't list your reminders.")
+	}
+
+	var blocks []slack.Block
+	for _, rem := range list {
+		if rem.CreatorID != userID {
+			continue
+		}
+		text := fmt.Sprintf("*%s*\n%s, %s, next %s", rem.Text, reminderTarget(rem, userID), rem.When, slackDate(rem.NextRun))
+		cancel := slack.NewButtonBlockElement(reminderCancelActionID, rem.ID, slack.NewTextBlockObject(slack.PlainTextType, "Cancel", false, false))
+		cancel.Style = slack.StyleDanger
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, slack.NewAccessory(cancel)))
+	}
+	if len(blocks) == 0 {
+		return ephemeral("You have no reminders.")
+	}
+
+	msg := ephemeral("Your reminders")
+	msg.Blocks = slack.Blocks{BlockSet: blocks}
+	return msg
+}
+
+func (b *Bot) cancelReminder(ctx context.Context, r *reminders, interaction slack.InteractionCallback) {
+	teamID, userID := interaction.Team.ID, interaction.User.ID
+	for _, action := range interaction.ActionCallback.BlockActions {
+		if action.ActionID != reminderCancelActionID {
+			continue
+		}
+		rem, err := r.get(ctx, teamID, action.Value)
+		if err != nil {
+			if err != ErrNotFound {
+				b.logger().Error("Failed to get reminder", Fields{"team_id": teamID, "reminder_id": action.Value, "error": err})
+			}
+			continue
+		}
+		// the list is only shown to its creator, but action values can be forged
+		if rem.CreatorID != userID {
+			continue
+		}
+		if err := r.storage.Delete(ctx, reminderKey(teamID, rem.ID)); err != nil {
+			b.logger().Error("Failed to cancel reminder", Fields{"team_id": teamID, "reminder_id": rem.ID, "error": err})
+			continue
+		}
+		b.logger().Debug("Cancelled reminder", Fields{"team_id": teamID, "user_id": userID, "reminder_id": rem.ID})
+	}
+
+	if interaction.ResponseURL == "" {
+		return
+	}
+	msg := b.reminderList(ctx, r, teamID, userID)
+	msg.ReplaceOriginal = true
+	if err := b.RespondContext(ctx, interaction.ResponseURL, msg); err != nil {
+		b.logger().Error("Failed to update reminder list", Fields{"team_id": teamID, "user_id": userID, "error": err})
+	}
+}
+
+// deliverReminders posts every reminder due by now, then deletes one-off reminders and reschedules recurring ones.
+// Reminders which were due while the bot was down are posted late, once.
+func (b *Bot) deliverReminders(ctx context.Context, r *reminders, now time.Time) {
+	list, err := r.list(ctx, "")
+	if err != nil {
+		b.logger().Error("Failed to list reminders", Fields{"error": err})
+		return
+	}
+
+	for _, rem := range list {
+		if rem.NextRun.After(now) {
+			continue
+		}
+		// reminders left due are delivered once the bot is no longer throttled
+		if b.Throttled() {
+			b.logger().Warn("Paused reminder delivery while throttled", Fields{})
+			return
+		}
+		fields := Fields{"team_id": rem.TeamID, "channel_id": rem.ChannelID, "reminder_id": rem.ID}
+
+		text := "Reminder: " + rem.Text
+		if rem.ChannelID != rem.CreatorID {
+			text = fmt.Sprintf("Reminder from <@%s>: %s", rem.CreatorID, rem.Text)
+		}
+		// a failed reminder is not retried, so one which can never be posted does not fail every minute
+		if _, _, err := b.ApiForTeam(rem.TeamID).PostMessageContext(ctx, rem.ChannelID, slack.MsgOptionText(text, false)); err != nil {
+			b.logger().Error("Failed to post reminder", Fields{"team_id": rem.TeamID, "channel_id": rem.ChannelID, "reminder_id": rem.ID, "error": err})
+		} else {
+			b.logger().Debug("Posted reminder", fields)
+		}
+
+		if rem.Cron == "" {
+			err = r.storage.Delete(ctx, reminderKey(rem.TeamID, rem.ID))
+		} else {
+			err = b.rescheduleReminder(ctx, r, rem, now)
+		}
+		if err != nil {
+			b.logger().Error("Failed to update reminder", Fields{"team_id": rem.TeamID, "reminder_id": rem.ID, "error": err})
+		}
+	}
+}
+
+// rescheduleReminder saves the next run of a recurring reminder unless it was cancelled while it was being posted.
+// Storage has no compare-and-set, so a cancel between the read and the save is still overwritten.
+func (b *Bot) rescheduleReminder(ctx context.Context, r *reminders, rem *reminder, now time.Time) error {
+	if _, err := r.get(ctx, rem.TeamID, rem.ID); err != nil {
+		if err == ErrNotFound {
+			b.logger().Debug("Reminder cancelled during delivery", Fields{"team_id": rem.TeamID, "reminder_id": rem.ID})
+			return nil
+		}
+		return err
+	}
+	if err := rem.reschedule(now); err != nil {
+		return err
+	}
+	return r.save(ctx, rem)
+}
+
+func (rem *reminder) reschedule(now time.Time) error {
+	schedule, err := parseCron(rem.Cron)
+	if err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(rem.Timezone)
+	if err != nil {
+		return err
+	}
+	rem.NextRun = schedule.next(now.In(location))
+	return nil
+}
+
+func reminderKey(teamID string, id string) string {
+	return reminderKeyPrefix + teamID + "/" + id
+}
+
+func (r *reminders) save(ctx context.Context, rem *reminder) error {
+	value, err := json.Marshal(rem)
+	if err != nil {
+		return err
+	}
+	return r.storage.Set(ctx, reminderKey(rem.TeamID, rem.ID), value)
+}
+
+func (r *reminders) get(ctx context.Context, teamID string, id string) (*reminder, error) {
+	value, err := r.storage.Get(ctx, reminderKey(teamID, id))
+	if err != nil {
+		return nil, err
+	}
+	var rem reminder
+	if err := json.Unmarshal(value, &rem); err != nil {
+		return nil, err
+	}
+	return &rem, nil
+}
+
+// list returns the reminders of a team, or of every team when teamID is empty, soonest first
+func (r *reminders) list(ctx context.Context, teamID string) ([]*reminder, error) {
+	prefix := reminderKeyPrefix
+	if teamID != "" {
+		prefix = reminderKey(teamID, "")
+	}
+	values, err := r.storage.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*reminder, 0, len(values))
+	for _, value := range values {
+		var rem reminder
+		if err := json.Unmarshal(value, &rem); err != nil {
+			return nil, err
+		}
+		list = append(list, &rem)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].NextRun.Equal(list[j].NextRun) {
+			return list[i].NextRun.Before(list[j].NextRun)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func reminderTarget(rem *reminder, userID string) string {
+	switch {
+	case rem.ChannelID == userID:
+		return "you"
+	case strings.HasPrefix(rem.ChannelID, "U") || strings.HasPrefix(rem.ChannelID, "W"):
+		return "<@" + rem.ChannelID + ">"
+	default:
+		return "<#" + rem.ChannelID + ">"
+	}
+}
+
+// slackDate formats a time for Slack to show in the reader's timezone
+func slackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} at {time}|%s>", t.Unix(), t.Format("Mon Jan 2 15:04 MST"))
+}
+
+var (
+	escapedTarget = regexp.MustCompile(`^<([#@])([A-Z0-9]+)(\|[^>]*)?>$`)
+	inDuration    = regexp.MustCompile(`^(\d+)\s*(m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?)$`)
+	clockTime     = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// parseReminder parses "<target> [to] <text> <when>" into a reminder due after now. The target is left as "me" or
+// "here", or the ID of the escaped channel or user.
+func parseReminder(text string, now time.Time) (*reminder, error) {
+	tokens := strings.Fields(text)
+	if len(tokens) < 3 {
+		return nil, errReminderUsage
+	}
+
+	target := strings.ToLower(tokens[0])
+	if target != "me" && target != "here" {
+		match := escapedTarget.FindStringSubmatch(tokens[0])
+		if match == nil {
+			return nil, errReminderUsage
+		}
+		target = match[2]
+	}
+
+	// the longest suffix which is a time leaves the rest as the text
+	start := 1
+	if strings.EqualFold(tokens[1], "to") {
+		start = 2
+	}
+	for i := start + 1; i < len(tokens); i++ {
+		when := strings.Join(tokens[i:], " ")
+		next, cron, err := parseReminderTime(when, now)
+		if err != nil {
+			continue
+		}
+		return &reminder{
+			ChannelID: target,
+			Text:      strings.Join(tokens[start:i], " "),
+			When:      when,
+			Cron:      cron,
+			NextRun:   next,
+		}, nil
+	}
+	return nil, errReminderUsage
+}
+
+// parseReminderTime parses when a reminder is due, returning the next time after now, in now's location, and a cron
+// expression for recurring reminders
+func parseReminderTime(when string, now time.Time) (time.Time, string, error) {
+	tokens := strings.Fields(strings.ToLower(when))
+	if len(tokens) == 0 {
+		return time.Time{}, "", errReminderUsage
+	}
+
+	switch tokens[0] {
+	case "in":
+		d, err := parseInDuration(strings.Join(tokens[1:], " "))
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		return now.Add(d), "", nil
+	case "every":
+		days, rest, err := parseEveryDays(tokens[1:])
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		hour, minute, err := parseAt(rest)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		cron := fmt.Sprintf("%d %d * * %s", minute, hour, days)
+		schedule, err := parseCron(cron)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		return schedule.next(now), cron, nil
+	}
+
+	day, rest := now, tokens[1:]
+	switch tokens[0] {
+	case "today":
+	case "tomorrow":
+		day = now.AddDate(0, 0, 1)
+	case "at":
+		rest = tokens
+	default:
+		if tokens[0] == "on" {
+			rest = tokens[1:]
+		} else {
+			rest = tokens
+		}
+		if len(rest) == 0 {
+			return time.Time{}, "", errReminderUsage
+		}
+		weekday, exists := parseWeekday(rest[0])
+		if !exists {
+			return time.Time{}, "", errReminderUsage
+		}
+		day = now.AddDate(0, 0, (int(weekday)-int(now.Weekday())+7)%7)
+		rest = rest[1:]
+	}
+	hour, minute, err := parseAt(rest)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	next := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		switch tokens[0] {
+		case "at":
+			next = next.AddDate(0, 0, 1)
+		case "today", "tomorrow":
+			return time.Time{}, "", errReminderUsage
+		default:
+			// the named weekday is today, and its time has passed
+			next = next.AddDate(0, 0, 7)
+		}
+	}
+	return next, "", nil
+}
+
+func parseInDuration(s string) (time.Duration, error) {
+	if match := inDuration.FindStringSubmatch(s); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		unit := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[match[2][0]]
+		if n > 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(strings.Replace(s, " ", "", -1))
+	if err != nil || d < time.Minute {
+		return 0, errReminderUsage
+	}
+	return d, nil
+}
+
+// parseEveryDays parses the days of "every ...", returning them as a cron day of week field and the remaining tokens
+func parseEveryDays(tokens []string) (string, []string, error) {
+	if len(tokens) == 0 {
+		return "", nil, errReminderUsage
+	}
+	switch tokens[0] {
+	case "day":
+		return "*", tokens[1:], nil
+	case "weekday", "weekdays":
+		return "1-5", tokens[1:], nil
+	}
+
+	var days []string
+	i := 0
+	for ; i < len(tokens); i++ {
+		token := strings.Trim(tokens[i], ",")
+		if token == "and" || token == "" {
+			continue
+		}
+		weekday, exists := parseWeekday(strings.TrimSuffix(token, "s"))
+		if !exists {
+			break
+		}
+		days = append(days, strconv.Itoa(int(weekday)))
+	}
+	if len(days) == 0 {
+		return "", nil, errReminderUsage
+	}
+	return strings.Join(days, ","), tokens[i:], nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseAt parses an optional "at <time>", defaulting to 9am
+func parseAt(tokens []string) (hour int, minute int, err error) {
+	if len(tokens) == 0 {
+		return 9, 0, nil
+	}
+	if tokens[0] != "at" || len(tokens) == 1 {
+		return 0, 0, errReminderUsage
+	}
+
+	clock := strings.Join(tokens[1:], "")
+	switch clock {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+	match := clockTime.FindStringSubmatch(clock)
+	if match == nil {
+		return 0, 0, errReminderUsage
+	}
+	hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, errReminderUsage
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, errReminderUsage
+	}
+	return hour, minute, nil
+}
@@ -0,0 +1,280 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseReminderTime(t *testing.T) {
+	// Friday 10:00
+	now := time.Date(2021, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		when string
+		next time.Time
+		cron string
+	}{
+		{"in 2h", now.Add(2 * time.Hour), ""},
+		{"in 90 minutes", now.Add(90 * time.Minute), ""},
+		{"in 1h30m", now.Add(90 * time.Minute), ""},
+		{"in 2 days", now.Add(48 * time.Hour), ""},
+		{"at 5pm", time.Date(2021, 10, 15, 17, 0, 0, 0, time.UTC), ""},
+		{"at 9:30am", time.Date(2021, 10, 16, 9, 30, 0, 0, time.UTC), ""},
+		{"at 17:45", time.Date(2021, 10, 15, 17, 45, 0, 0, time.UTC), ""},
+		{"today at noon", time.Date(2021, 10, 15, 12, 0, 0, 0, time.UTC), ""},
+		{"tomorrow", time.Date(2021, 10, 16, 9, 0, 0, 0, time.UTC), ""},
+		{"tomorrow at midnight", time.Date(2021, 10, 16, 0, 0, 0, 0, time.UTC), ""},
+		{"monday", time.Date(2021, 10, 18, 9, 0, 0, 0, time.UTC), ""},
+		{"on Friday at 8am", time.Date(2021, 10, 22, 8, 0, 0, 0, time.UTC), ""},
+		{"friday at 11am", time.Date(2021, 10, 15, 11, 0, 0, 0, time.UTC), ""},
+		{"every day", time.Date(2021, 10, 16, 9, 0, 0, 0, time.UTC), "0 9 * * *"},
+		{"every weekday at 9:30am", time.Date(2021, 10, 18, 9, 30, 0, 0, time.UTC), "30 9 * * 1-5"},
+		{"every Monday and Thursday at noon", time.Date(2021, 10, 18, 12, 0, 0, 0, time.UTC), "0 12 * * 1,4"},
+		{"every fridays at 4pm", time.Date(2021, 10, 15, 16, 0, 0, 0, time.UTC), "0 16 * * 5"},
+	}
+	for _, test := range tests {
+		next, cron, err := parseReminderTime(test.when, now)
+		if assert.NoError(t, err, test.when) {
+			assert.Equal(t, test.next, next, test.when)
+			assert.Equal(t, test.cron, cron, test.when)
+		}
+	}
+
+	for _, when := range []string{"", "in", "in 0m", "in 30s", "at", "at 13pm", "at 25:00", "today at 9am", "someday", "every", "every other day", "tomorrow at", "at 5pm sharp"} {
+		_, _, err := parseReminderTime(when, now)
+		assert.Error(t, err, when)
+	}
+}
+
+func TestParseReminder(t *testing.T) {
+	now := time.Date(2021, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	rem, err := parseReminder("me to call in at the office in 2h", now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "me", rem.ChannelID)
+		assert.Equal(t, "call in at the office", rem.Text)
+		assert.Equal(t, "in 2h", rem.When)
+		assert.Equal(t, now.Add(2*time.Hour), rem.NextRun)
+	}
+
+	rem, err = parseReminder("<#C123|team> standup every weekday at 9:30am", now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "C123", rem.ChannelID)
+		assert.Equal(t, "standup", rem.Text)
+		assert.Equal(t, "30 9 * * 1-5", rem.Cron)
+	}
+
+	rem, err = parseReminder("<@U2> to review the PR tomorrow", now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "U2", rem.ChannelID)
+		assert.Equal(t, "review the PR", rem.Text)
+	}
+
+	for _, text := range []string{"", "me in 2h", "me to stretch", "#team standup every day", "me to in 2h"} {
+		_, err := parseReminder(text, now)
+		assert.Error(t, err, text)
+	}
+}
+
+// newRemindersSlack answers users.info with a timezone
+func newRemindersSlack() *fakeSlack {
+	server := newFakeSlack()
+	server.setResponse("users.info", map[string]interface{}{"ok": true, "user": map[string]interface{}{"id": "U1", "tz": "America/Chicago"}})
+	return server
+}
+
+// postedReminders returns the channel and text of every message posted to a fake
+func postedReminders(server *fakeSlack) []string {
+	var posted []string
+	for _, call := range server.callsTo("chat.postMessage") {
+		posted = append(posted, call.form.Get("channel")+" "+call.form.Get("text"))
+	}
+	return posted
+}
+
+func postReminderCommand(t *testing.T, engine *gin.Engine, text string) *slack.Msg {
+	var msg slack.Msg
+	e := getHttpExpect(t, engine)
+	body := e.POST("/slack/commands").
+		WithFormField("command", "/remind").
+		WithFormField("team_id", "T1").
+		WithFormField("user_id", "U1").
+		WithFormField("channel_id", "C1").
+		WithFormField("text", text).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+	assert.NoError(t, json.Unmarshal([]byte(body), &msg))
+	return &msg
+}
+
+func TestRegisterReminders(t *testing.T) {
+	server := newRemindersSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	storage := NewMemoryStorage()
+	bot := server.newBot()
+	defer bot.Shutdown(time.Second)
+	bot.RegisterReminders(ReminderOptions{Storage: storage})
+	bot.prepareEngine(engine, false)
+
+	msg := postReminderCommand(t, engine, "me to stretch in 2h")
+	assert.Equal(t, slack.ResponseTypeEphemeral, msg.ResponseType)
+	assert.Contains(t, msg.Text, `I will remind you "stretch" in 2h`)
+
+	msg = postReminderCommand(t, engine, "here standup every weekday at 9am")
+	assert.Contains(t, msg.Text, `I will remind <#C1> "standup" every weekday at 9am`)
+
+	msg = postReminderCommand(t, engine, "me to stretch")
+	assert.Contains(t, msg.Text, "Sorry, I didn't understand that.")
+
+	r := &reminders{storage: storage}
+	list, _ := r.list(context.Background(), "T1")
+	if !assert.Equal(t, 2, len(list)) {
+		return
+	}
+	assert.Equal(t, "America/Chicago", list[0].Timezone)
+
+	// only the one-off reminder is due in 3 hours, unless that is after 9am on a weekday in Chicago
+	now := time.Now().Add(3 * time.Hour)
+	recurring := list[1]
+	if list[1].Cron == "" {
+		recurring = list[0]
+	}
+	bot.deliverReminders(context.Background(), r, now)
+	assert.Contains(t, postedReminders(server), "U1 Reminder: stretch")
+
+	list, _ = r.list(context.Background(), "T1")
+	if assert.Equal(t, 1, len(list)) {
+		assert.Equal(t, recurring.ID, list[0].ID)
+		assert.True(t, list[0].NextRun.After(now))
+	}
+}
+
+func TestDeliverRecurringReminder(t *testing.T) {
+	server := newRemindersSlack()
+	defer server.Close()
+
+	bot := server.newBot()
+	r := &reminders{storage: NewMemoryStorage()}
+	ctx := context.Background()
+
+	due := time.Date(2021, 10, 15, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, r.save(ctx, &reminder{ID: "a", TeamID: "T1", CreatorID: "U1", ChannelID: "C1", Text: "standup", Cron: "0 9 * * 1-5", Timezone: "UTC", NextRun: due}))
+
+	bot.deliverReminders(ctx, r, due.Add(time.Minute))
+	assert.Equal(t, []string{"C1 Reminder from <@U1>: standup"}, postedReminders(server))
+
+	rem, err := r.get(ctx, "T1", "a")
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2021, 10, 18, 9, 0, 0, 0, time.UTC), rem.NextRun)
+	}
+
+	bot.deliverReminders(ctx, r, due.Add(2*time.Minute))
+	assert.Equal(t, 1, len(postedReminders(server)))
+}
+
+func TestDeliverRecurringReminderCancelledDuringDelivery(t *testing.T) {
+	server := newRemindersSlack()
+	defer server.Close()
+
+	bot := server.newBot()
+	r := &reminders{storage: NewMemoryStorage()}
+	ctx := context.Background()
+
+	due := time.Date(2021, 10, 15, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, r.save(ctx, &reminder{ID: "a", TeamID: "T1", CreatorID: "U1", ChannelID: "C1", Text: "standup", Cron: "0 9 * * 1-5", Timezone: "UTC", NextRun: due}))
+	// the creator cancels the reminder while it is being posted
+	server.setResponseFunc("chat.postMessage", func(call apiCall) interface{} {
+		assert.NoError(t, r.storage.Delete(ctx, reminderKey("T1", "a")))
+		return map[string]interface{}{"ok": true, "channel": "C1", "ts": "1.1"}
+	})
+
+	bot.deliverReminders(ctx, r, due.Add(time.Minute))
+	assert.Equal(t, 1, len(postedReminders(server)))
+	_, err := r.get(ctx, "T1", "a")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestDeliverRemindersPausedWhileThrottled(t *testing.T) {
+	server := newRemindersSlack()
+	defer server.Close()
+
+	bot := server.newBot(OptionBackpressure(BackpressureOptions{PauseFor: time.Hour}))
+	r := &reminders{storage: NewMemoryStorage()}
+	ctx := context.Background()
+
+	due := time.Now().Add(-time.Minute)
+	assert.NoError(t, r.save(ctx, &reminder{ID: "a", TeamID: "T1", CreatorID: "U1", ChannelID: "U1", Text: "stretch", NextRun: due}))
+	bot.applyBackpressure(ctx, newAppRateLimitedEvent(newAppRateLimited(time.Now())))
+
+	bot.deliverReminders(ctx, r, time.Now())
+	assert.Empty(t, postedReminders(server))
+	_, err := r.get(ctx, "T1", "a")
+	assert.NoError(t, err)
+}
+
+func TestCancelReminder(t *testing.T) {
+	server := newRemindersSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	storage := NewMemoryStorage()
+	bot := server.newBot()
+	defer bot.Shutdown(time.Second)
+	bot.RegisterReminders(ReminderOptions{Storage: storage})
+	bot.prepareEngine(engine, false)
+
+	r := &reminders{storage: storage}
+	ctx := context.Background()
+	next := time.Now().Add(time.Hour)
+	assert.NoError(t, r.save(ctx, &reminder{ID: "a", TeamID: "T1", CreatorID: "U1", ChannelID: "U1", Text: "stretch", When: "in 1h", NextRun: next}))
+	assert.NoError(t, r.save(ctx, &reminder{ID: "b", TeamID: "T1", CreatorID: "U1", ChannelID: "C1", Text: "lunch", When: "at noon", NextRun: next.Add(time.Hour)}))
+	assert.NoError(t, r.save(ctx, &reminder{ID: "c", TeamID: "T1", CreatorID: "U2", ChannelID: "U2", Text: "not mine", When: "in 1h", NextRun: next}))
+
+	msg := postReminderCommand(t, engine, "list")
+	if assert.Equal(t, 2, len(msg.Blocks.BlockSet)) {
+		section := msg.Blocks.BlockSet[0].(*slack.SectionBlock)
+		assert.Contains(t, section.Text.Text, "*stretch*")
+		assert.Equal(t, "a", section.Accessory.ButtonElement.Value)
+	}
+
+	cancel := func(userID string, id string) {
+		postInteraction(t, engine, slack.InteractionCallback{
+			Type:        slack.InteractionTypeBlockActions,
+			Team:        slack.Team{ID: "T1"},
+			User:        slack.User{ID: userID},
+			ResponseURL: server.responseURL(),
+			ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+				{ActionID: reminderCancelActionID, Value: id},
+			}},
+		})
+	}
+
+	// another user cannot cancel the reminder
+	cancel("U2", "a")
+	_, err := r.get(ctx, "T1", "a")
+	assert.NoError(t, err)
+
+	cancel("U1", "a")
+	_, err = r.get(ctx, "T1", "a")
+	assert.Equal(t, ErrNotFound, err)
+
+	responded := server.responded()
+	if assert.Equal(t, 2, len(responded)) {
+		assert.True(t, responded[1].ReplaceOriginal)
+		assert.Equal(t, 1, len(responded[1].Blocks.BlockSet))
+	}
+
+	cancel("U1", "b")
+	if responded := server.responded(); assert.Equal(t, 3, len(responded)) {
+		assert.Equal(t, "You have no reminders.", responded[2].Text)
+	}
+}
@@ -0,0 +1,131 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"strings"
+)
+
+// messageRef locates a message which replies and reactions are made against
+type messageRef struct {
+	bot      *Bot
+	ctx      context.Context
+	teamID   string
+	channel  string
+	user     string
+	ts       string
+	threadTS string
+}
+
+func (r messageRef) api() *slack.Client {
+	return r.bot.ApiForTeam(r.teamID)
+}
+
+// post sends a message to the channel, in threadTS when it is set
+func (r messageRef) post(threadTS string, text string, options []slack.MsgOption) (string, error) {
+	options = append([]slack.MsgOption{slack.MsgOptionText(text, false)}, options...)
+	if threadTS != "" {
+		options = append(options, slack.MsgOptionTS(threadTS))
+	}
+	_, ts, err := r.api().PostMessageContext(r.ctx, r.channel, options...)
+	return ts, err
+}
+
+// reply posts where the message was posted: in its thread if it is a thread reply, otherwise in the channel
+func (r messageRef) reply(text string, options []slack.MsgOption) (string, error) {
+	threadTS := ""
+	if r.threadTS != "" && r.threadTS != r.ts {
+		threadTS = r.threadTS
+	}
+	return r.post(threadTS, text, options)
+}
+
+// replyInThread posts in the message's thread, starting one if there is none
+func (r messageRef) replyInThread(text string, options []slack.MsgOption) (string, error) {
+	threadTS := r.threadTS
+	if threadTS == "" {
+		threadTS = r.ts
+	}
+	return r.post(threadTS, text, options)
+}
+
+// replyEphemeral posts a message only the author of the message can see, in its thread if it is a thread reply
+func (r messageRef) replyEphemeral(text string, options []slack.MsgOption) (string, error) {
+	options = append([]slack.MsgOption{slack.MsgOptionText(text, false)}, options...)
+	if r.threadTS != "" && r.threadTS != r.ts {
+		options = append(options, slack.MsgOptionTS(r.threadTS))
+	}
+	return r.api().PostEphemeralContext(r.ctx, r.channel, r.user, options...)
+}
+
+func (r messageRef) react(emoji string) error {
+	return r.api().AddReactionContext(r.ctx, strings.Trim(emoji, ":"), slack.NewRefToMessage(r.channel, r.ts))
+}
+
+func (c MessageEventContainer) ref() messageRef {
+	return messageRef{
+		bot:      c.bot,
+		ctx:      c.Context(),
+		teamID:   c.APIEvent.TeamID,
+		channel:  c.Event.Channel,
+		user:     c.Event.User,
+		ts:       c.Event.TimeStamp,
+		threadTS: c.Event.ThreadTimeStamp,
+	}
+}
+
+// Reply where the message was posted: in its thread if it is a thread reply, otherwise in the channel.
+// Returns the timestamp of the reply.
+func (c MessageEventContainer) Reply(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().reply(text, options)
+}
+
+// Reply in the thread of the message, starting one if there is none. Pass slack.MsgOptionBroadcast() to also
+// send the reply to the channel. Returns the timestamp of the reply.
+func (c MessageEventContainer) ReplyInThread(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().replyInThread(text, options)
+}
+
+// Reply with a message only the author of the message can see
+func (c MessageEventContainer) ReplyEphemeral(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().replyEphemeral(text, options)
+}
+
+// React to the message with an emoji name, with or without surrounding colons
+func (c MessageEventContainer) React(emoji string) error {
+	return c.ref().react(emoji)
+}
+
+func (c AppMentionEventContainer) ref() messageRef {
+	return messageRef{
+		bot:      c.bot,
+		ctx:      c.Context(),
+		teamID:   c.APIEvent.TeamID,
+		channel:  c.Event.Channel,
+		user:     c.Event.User,
+		ts:       c.Event.TimeStamp,
+		threadTS: c.Event.ThreadTimeStamp,
+	}
+}
+
+// Reply where the mention was posted: in its thread if it is a thread reply, otherwise in the channel.
+// Returns the timestamp of the reply.
+func (c AppMentionEventContainer) Reply(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().reply(text, options)
+}
+
+// Reply in the thread of the mention, starting one if there is none. Pass slack.MsgOptionBroadcast() to also
+// send the reply to the channel. Returns the timestamp of the reply.
+func (c AppMentionEventContainer) ReplyInThread(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().replyInThread(text, options)
+}
+
+// Reply with a message only the user who mentioned the bot can see
+func (c AppMentionEventContainer) ReplyEphemeral(text string, options ...slack.MsgOption) (string, error) {
+	return c.ref().replyEphemeral(text, options)
+}
+
+// React to the mention with an emoji name, with or without surrounding colons
+func (c AppMentionEventContainer) React(emoji string) error {
+	return c.ref().react(emoji)
+}
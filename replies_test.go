@@ -0,0 +1,129 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newReplyMessage(bot *Bot, ts string, threadTS string) MessageEventContainer {
+	return MessageEventContainer{
+		APIEvent: slackevents.EventsAPIEvent{TeamID: "T1"},
+		Event:    slackevents.MessageEvent{Channel: "C1", User: "U1", TimeStamp: ts, ThreadTimeStamp: threadTS},
+		bot:      bot,
+	}
+}
+
+func TestReplyTopLevel(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	ts, err := newReplyMessage(bot, "1.1", "").Reply("hello")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000.000001", ts)
+	calls := server.callsTo("")
+	assert.Equal(t, "chat.postMessage", calls[0].method)
+	assert.Equal(t, "C1", calls[0].form.Get("channel"))
+	assert.Equal(t, "hello", calls[0].form.Get("text"))
+	assert.Equal(t, "", calls[0].form.Get("thread_ts"))
+}
+
+func TestReplyToThreadReply(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	_, err := newReplyMessage(bot, "1.2", "1.1").Reply("hello")
+
+	assert.NoError(t, err)
+	calls := server.callsTo("")
+	assert.Equal(t, "1.1", calls[0].form.Get("thread_ts"))
+}
+
+func TestReplyInThread(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	_, err := newReplyMessage(bot, "1.1", "").ReplyInThread("started", slack.MsgOptionBroadcast())
+	assert.NoError(t, err)
+	_, err = newReplyMessage(bot, "1.2", "1.1").ReplyInThread("continued")
+	assert.NoError(t, err)
+
+	calls := server.callsTo("")
+	assert.Equal(t, "1.1", calls[0].form.Get("thread_ts"))
+	assert.Equal(t, "true", calls[0].form.Get("reply_broadcast"))
+	assert.Equal(t, "1.1", calls[1].form.Get("thread_ts"))
+}
+
+func TestReplyEphemeral(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	c := AppMentionEventContainer{
+		APIEvent: slackevents.EventsAPIEvent{TeamID: "T1"},
+		Event:    slackevents.AppMentionEvent{Channel: "C1", User: "U1", TimeStamp: "1.1"},
+		bot:      bot,
+	}
+	_, err := c.ReplyEphemeral("only you")
+
+	assert.NoError(t, err)
+	calls := server.callsTo("")
+	assert.Equal(t, "chat.postEphemeral", calls[0].method)
+	assert.Equal(t, "U1", calls[0].form.Get("user"))
+	assert.Equal(t, "only you", calls[0].form.Get("text"))
+}
+
+func TestReact(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	err := newReplyMessage(bot, "1.1", "").React(":thumbsup:")
+
+	assert.NoError(t, err)
+	calls := server.callsTo("")
+	assert.Equal(t, "reactions.add", calls[0].method)
+	assert.Equal(t, "thumbsup", calls[0].form.Get("name"))
+	assert.Equal(t, "1.1", calls[0].form.Get("timestamp"))
+}
+
+func TestReplyUsesTeamToken(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot(OptionTeamTokens(func(teamID string) string {
+		if teamID == "T1" {
+			return "team-token"
+		}
+		return ""
+	}))
+
+	_, err := newReplyMessage(bot, "1.1", "").Reply("hello")
+
+	assert.NoError(t, err)
+	calls := server.callsTo("")
+	assert.Equal(t, "team-token", calls[0].token)
+}
+
+func TestDispatchedContainerCanReply(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	bot := server.newBot()
+
+	bot.RegisterMessageEvent(func(bot *Bot, c MessageEventContainer) {
+		_, err := c.Reply("pong")
+		assert.NoError(t, err)
+	})
+	for _, callback := range bot.eventCallbacks(slackevents.Message) {
+		callback(context.Background(), bot, newMessageEvent("ping"))
+	}
+
+	calls := server.callsTo("")
+	assert.Equal(t, 1, len(calls))
+	assert.Equal(t, "pong", calls[0].form.Get("text"))
+}
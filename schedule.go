@@ -0,0 +1,318 @@
+package slackbot
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A job run on a schedule. ctx is cancelled when the job is unscheduled or the bot shuts down.
+type ScheduledJob = func(ctx context.Context, bot *Bot)
+
+// Options for OptionScheduler
+type SchedulerOptions struct {
+	// Storage electing the replica which runs scheduled jobs, for bots running several replicas.
+	// Without it every replica runs every job.
+	Storage Storage
+	// Name of this replica in leader election, unique among replicas. Defaults to the hostname and a random suffix.
+	Owner string
+	// How long a leader keeps leadership without renewing it. Defaults to 30 seconds.
+	LeaseTTL time.Duration
+	// Largest random delay added to each run, spreading jobs scheduled at the same time. Defaults to none.
+	Jitter time.Duration
+}
+
+const schedulerLeaderKey = "slackbot/scheduler/leader"
+
+type scheduler struct {
+	options SchedulerOptions
+	running bool
+	// set by Shutdown until the bot is booted again, so that scheduling a job does not start the scheduler
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	jobs    map[Handle]*scheduledJob
+	leader  int32
+	// closed once this replica knows whether it leads
+	elected chan struct{}
+	// waits until the next run, or until ctx is done
+	sleep func(ctx context.Context, d time.Duration) error
+	wg    sync.WaitGroup
+	sync.Mutex
+}
+
+func newScheduler(options SchedulerOptions) *scheduler {
+	if options.Owner == "" {
+		hostname, _ := os.Hostname()
+		options.Owner = hostname + "-" + newRequestID()
+	}
+	if options.LeaseTTL <= 0 {
+		options.LeaseTTL = 30 * time.Second
+	}
+	return &scheduler{options: options, jobs: make(map[Handle]*scheduledJob), sleep: sleepContext}
+}
+
+// A job kept while the scheduler is stopped, so it resumes when the bot is booted again
+type scheduledJob struct {
+	schedule jobSchedule
+	location *time.Location
+	fields   Fields
+	job      ScheduledJob
+	// cancels the running job; nil while the scheduler is stopped
+	cancel context.CancelFunc
+}
+
+// Schedule a job with a five field cron expression, such as "0 9 * * 1-5" for 9am on weekdays, or a descriptor such
+// as "@daily", evaluated in the IANA timezone tz, or UTC when tz is empty. A run is skipped while the previous run of
+// the job is still running, or while the bot is Throttled. Jobs run until they are unscheduled. They stop when the bot
+// shuts down and resume when it is booted again.
+func (b *Bot) Schedule(cronExpr string, tz string, job ScheduledJob) (Handle, error) {
+	schedule, err := parseCron(cronExpr)
+	if err != nil {
+		return 0, err
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.scheduleJob(schedule, location, Fields{"cron": cronExpr, "timezone": location.String()}, job), nil
+}
+
+// jobSchedule gives the next time a job runs after t
+type jobSchedule interface {
+	next(t time.Time) time.Time
+}
+
+func (b *Bot) scheduleJob(schedule jobSchedule, location *time.Location, fields Fields, job ScheduledJob) Handle {
+	b.Lock()
+	if b.scheduler == nil {
+		b.scheduler = newScheduler(SchedulerOptions{})
+	}
+	s := b.scheduler
+	handle := b.nextHandle()
+	b.Unlock()
+
+	fields["handle"] = handle
+	b.logger().Debug("Scheduled job", fields)
+
+	s.Lock()
+	defer s.Unlock()
+
+	j := &scheduledJob{schedule: schedule, location: location, fields: fields, job: job}
+	s.jobs[handle] = j
+	switch {
+	case s.running:
+		s.startJob(b, j)
+	case !s.stopped:
+		s.start(b)
+	}
+	return handle
+}
+
+// Unschedule a job using the Handle returned when it was scheduled, cancelling its context if it is running
+func (b *Bot) Unschedule(handle Handle) {
+	b.logger().Debug("Unscheduled job", Fields{"handle": handle})
+
+	b.RLock()
+	s := b.scheduler
+	b.RUnlock()
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if j, exists := s.jobs[handle]; exists {
+		if j.cancel != nil {
+			j.cancel()
+		}
+		delete(s.jobs, handle)
+	}
+}
+
+// start runs every scheduled job, and must be called with the scheduler locked
+func (s *scheduler) start(b *Bot) {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.running = true
+	s.elected = make(chan struct{})
+
+	if s.options.Storage == nil {
+		atomic.StoreInt32(&s.leader, 1)
+		close(s.elected)
+	} else {
+		atomic.StoreInt32(&s.leader, 0)
+		s.wg.Add(1)
+		go s.elect(s.ctx, b, s.elected)
+	}
+
+	for _, j := range s.jobs {
+		s.startJob(b, j)
+	}
+}
+
+// startJob must be called with the scheduler locked and running
+func (s *scheduler) startJob(b *Bot, j *scheduledJob) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	j.cancel = cancel
+	s.wg.Add(1)
+	go s.run(ctx, b, j.fields, j.schedule, j.location, j.job, s.elected)
+}
+
+// resume starts the scheduler again after it was stopped, if any job is scheduled
+func (s *scheduler) resume(b *Bot) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.stopped = false
+	if !s.running && len(s.jobs) > 0 {
+		s.start(b)
+	}
+}
+
+// stop cancels every job, keeping them to resume, and waits for running jobs until ctx is done
+func (s *scheduler) stop(ctx context.Context) {
+	s.Lock()
+	s.stopped = true
+	if !s.running {
+		s.Unlock()
+		return
+	}
+	s.cancel()
+	s.running = false
+	for _, j := range s.jobs {
+		j.cancel = nil
+	}
+	s.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// elect campaigns for leadership until ctx is done, renewing it well before the lease expires, and closes elected
+// after the first campaign
+func (s *scheduler) elect(ctx context.Context, b *Bot, elected chan struct{}) {
+	defer s.wg.Done()
+
+	storage, owner, ttl := s.options.Storage, s.options.Owner, s.options.LeaseTTL
+	for first := true; ; first = false {
+		leader, err := storage.Lock(ctx, schedulerLeaderKey, owner, ttl)
+		if err != nil {
+			b.logger().Error("Failed to elect scheduler leader", Fields{"owner": owner, "error": err})
+			leader = false
+		}
+		if leader != (atomic.SwapInt32(&s.leader, boolToInt32(leader)) == 1) {
+			b.logger().Info("Scheduler leadership changed", Fields{"owner": owner, "leader": leader})
+		}
+		if first {
+			close(elected)
+		}
+
+		if sleepContext(ctx, ttl/3) != nil {
+			if atomic.LoadInt32(&s.leader) == 1 {
+				// let another replica take over without waiting for the lease to expire
+				if err := storage.Unlock(context.Background(), schedulerLeaderKey, owner); err != nil {
+					b.logger().Warn("Failed to release scheduler leadership", Fields{"owner": owner, "error": err})
+				}
+			}
+			return
+		}
+	}
+}
+
+func (s *scheduler) isLeader() bool {
+	return atomic.LoadInt32(&s.leader) == 1
+}
+
+func (s *scheduler) jitter() time.Duration {
+	if s.options.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(s.options.Jitter)))
+}
+
+// run waits for each scheduled time of a job and runs it on the leader, once the first election is over, unless the
+// bot is throttled. Runs are never concurrent: scheduled times passing while the job runs are skipped.
+func (s *scheduler) run(ctx context.Context, b *Bot, fields Fields, schedule jobSchedule, location *time.Location, job ScheduledJob, elected <-chan struct{}) {
+	defer s.wg.Done()
+
+	last := time.Now().In(location)
+	for {
+		next := schedule.next(last)
+		if now := time.Now().In(location); !next.IsZero() && next.Before(now) {
+			b.logger().Warn("Skipped scheduled job, its previous run was still running", Fields{"handle": fields["handle"], "scheduled_at": next})
+			next = schedule.next(now)
+		}
+		if next.IsZero() {
+			b.logger().Warn("Scheduled job never runs", fields)
+			return
+		}
+
+		if s.sleep(ctx, time.Until(next)+s.jitter()) != nil || ctx.Err() != nil {
+			return
+		}
+		last = next
+
+		select {
+		case <-elected:
+		case <-ctx.Done():
+			return
+		}
+		if !s.isLeader() {
+			b.logger().Debug("Skipped scheduled job on follower", fields)
+			continue
+		}
+		if b.Throttled() {
+			b.logger().Warn("Skipped scheduled job while throttled", fields)
+			continue
+		}
+		s.runJob(ctx, b, fields, job)
+	}
+}
+
+func (s *scheduler) runJob(ctx context.Context, b *Bot, fields Fields, job ScheduledJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger().Error("Scheduled job panicked", Fields{"handle": fields["handle"], "cron": fields["cron"], "panic": r})
+		}
+	}()
+
+	started := time.Now()
+	b.logger().Debug("Running scheduled job", fields)
+	job(ctx, b)
+	b.logger().Debug("Finished scheduled job", Fields{"handle": fields["handle"], "duration": time.Since(started)})
+}
+
+func boolToInt32(value bool) int32 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func (b *Bot) stopScheduler(ctx context.Context) {
+	b.RLock()
+	s := b.scheduler
+	b.RUnlock()
+	if s != nil {
+		s.stop(ctx)
+	}
+}
+
+// resumeSchedulerLocked starts the jobs stopped by Shutdown, and must be called with the bot locked
+func (b *Bot) resumeSchedulerLocked() {
+	if b.scheduler != nil {
+		b.scheduler.resume(b)
+	}
+}
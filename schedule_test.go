@@ -0,0 +1,202 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// intervalSchedule runs a job every interval, which cron expressions cannot do below a minute
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// tickScheduler makes the scheduled jobs of a bot run when the returned channel is sent to rather than when they are
+// due. A send only completes once the job it wakes is waiting for its next run.
+func tickScheduler(bot *Bot) chan<- struct{} {
+	ticks := make(chan struct{})
+	bot.Lock()
+	defer bot.Unlock()
+	if bot.scheduler == nil {
+		bot.scheduler = newScheduler(SchedulerOptions{})
+	}
+	bot.scheduler.sleep = func(ctx context.Context, d time.Duration) error {
+		select {
+		case <-ticks:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ticks
+}
+
+func TestSchedule(t *testing.T) {
+	bot := newBot()
+	defer bot.Shutdown(time.Second)
+
+	_, err := bot.Schedule("61 * * * *", "", func(ctx context.Context, bot *Bot) {})
+	assert.Error(t, err)
+	_, err = bot.Schedule("@daily", "Nowhere/Special", func(ctx context.Context, bot *Bot) {})
+	assert.Error(t, err)
+
+	handle, err := bot.Schedule("0 9 * * 1-5", "America/Chicago", func(ctx context.Context, bot *Bot) {})
+	assert.NoError(t, err)
+	assert.NotZero(t, handle)
+	assert.Equal(t, 1, len(bot.scheduler.jobs))
+
+	bot.Unschedule(handle)
+	assert.Equal(t, 0, len(bot.scheduler.jobs))
+}
+
+func TestScheduledJobRuns(t *testing.T) {
+	bot := newBot()
+	defer bot.Shutdown(time.Second)
+	ticks := tickScheduler(bot)
+
+	runs := make(chan struct{}, 10)
+	handle := bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		runs <- struct{}{}
+	})
+
+	for i := 0; i < 3; i++ {
+		ticks <- struct{}{}
+		<-runs
+	}
+
+	// the job stops waiting for its next run once unscheduled
+	bot.Unschedule(handle)
+	bot.scheduler.wg.Wait()
+	assert.Equal(t, 0, len(runs))
+}
+
+func TestScheduledJobDoesNotOverlap(t *testing.T) {
+	bot := newBot()
+	defer bot.Shutdown(time.Second)
+	ticks := tickScheduler(bot)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		started <- struct{}{}
+		<-finish
+	})
+
+	ticks <- struct{}{}
+	<-started
+	// nothing waits for the next run while the job is running
+	select {
+	case ticks <- struct{}{}:
+		t.Fatal("scheduled job overlapped")
+	default:
+	}
+	finish <- struct{}{}
+
+	ticks <- struct{}{}
+	<-started
+	finish <- struct{}{}
+}
+
+func TestScheduledJobSkippedWhileThrottled(t *testing.T) {
+	bot := NewBot("token", "secret", OptionBackpressure(BackpressureOptions{PauseFor: time.Hour}))
+	defer bot.Shutdown(time.Second)
+	ticks := tickScheduler(bot)
+
+	var runs int32
+	bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		atomic.AddInt32(&runs, 1)
+	})
+	bot.applyBackpressure(context.Background(), newAppRateLimitedEvent(newAppRateLimited(time.Now())))
+
+	// the second tick completes once the first run has been skipped
+	ticks <- struct{}{}
+	ticks <- struct{}{}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+}
+
+func TestShutdownCancelsScheduledJobs(t *testing.T) {
+	bot := newBot()
+	ticks := tickScheduler(bot)
+
+	started := make(chan struct{})
+	var cancelled int32
+	bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		close(started)
+		<-ctx.Done()
+		atomic.StoreInt32(&cancelled, 1)
+	})
+
+	ticks <- struct{}{}
+	<-started
+	bot.Shutdown(time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cancelled))
+}
+
+func TestScheduledJobsResumeAfterBoot(t *testing.T) {
+	bot := newBot()
+	defer bot.Shutdown(time.Second)
+	ticks := tickScheduler(bot)
+
+	runs := make(chan struct{}, 10)
+	bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		runs <- struct{}{}
+	})
+	ticks <- struct{}{}
+	<-runs
+
+	bot.Shutdown(time.Second)
+	assert.Equal(t, 1, len(bot.scheduler.jobs))
+	// jobs scheduled while the bot is shut down wait for it to be booted
+	bot.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		runs <- struct{}{}
+	})
+	select {
+	case ticks <- struct{}{}:
+		t.Fatal("scheduled job ran while the bot was shut down")
+	default:
+	}
+
+	assert.NoError(t, bot.Boot("127.0.0.1:0"))
+	ticks <- struct{}{}
+	ticks <- struct{}{}
+	<-runs
+	<-runs
+}
+
+func TestScheduledJobsRunOnLeaderOnly(t *testing.T) {
+	storage := NewMemoryStorage()
+	runsA := make(chan struct{}, 10)
+	var runsB int32
+
+	a := NewBot("token", "secret", OptionScheduler(SchedulerOptions{Storage: storage, Owner: "a"}))
+	ticksA := tickScheduler(a)
+	a.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		runsA <- struct{}{}
+	})
+	// let a win the election
+	<-a.scheduler.elected
+
+	b := NewBot("token", "secret", OptionScheduler(SchedulerOptions{Storage: storage, Owner: "b"}))
+	ticksB := tickScheduler(b)
+	b.scheduleJob(intervalSchedule(time.Hour), time.UTC, Fields{}, func(ctx context.Context, bot *Bot) {
+		atomic.AddInt32(&runsB, 1)
+	})
+	<-b.scheduler.elected
+
+	ticksA <- struct{}{}
+	<-runsA
+	// the second tick completes once the first run has been skipped
+	ticksB <- struct{}{}
+	ticksB <- struct{}{}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runsB))
+
+	// shutting down releases leadership
+	a.Shutdown(time.Second)
+	b.Shutdown(time.Second)
+	locked, _ := storage.Lock(context.Background(), schedulerLeaderKey, "c", time.Minute)
+	assert.True(t, locked)
+}
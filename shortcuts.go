@@ -0,0 +1,120 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/slack-go/slack"
+)
+
+// Where a shortcut was triggered. It travels from the shortcut to the modal's submission in private_metadata.
+type ShortcutOrigin struct {
+	Type       slack.InteractionType `json:"type"`
+	CallbackID string                `json:"callback_id"`
+	TeamID     string                `json:"team_id,omitempty"`
+	UserID     string                `json:"user_id,omitempty"`
+	// Set for message shortcuts
+	ChannelID string `json:"channel_id,omitempty"`
+	MessageTs string `json:"message_ts,omitempty"`
+	ThreadTs  string `json:"thread_ts,omitempty"`
+	// private_metadata set by ShortcutModal.View, which is restored on the submitted view
+	Metadata string `json:"metadata,omitempty"`
+}
+
+// A modal opened by a shortcut, for RegisterShortcutModal and RegisterMessageShortcutModal
+type ShortcutModal struct {
+	// Build the modal to open. For message shortcuts interaction.Message holds the message, to pre-fill the modal.
+	// The modal's type is always set to modal and its callback ID to the shortcut's callback ID.
+	View func(bot *Bot, interaction slack.InteractionCallback) slack.ModalViewRequest
+	// Handle the submitted modal. Return a slack.ViewSubmissionResponse or nil to close the modal.
+	OnSubmit func(bot *Bot, interaction slack.InteractionCallback, origin ShortcutOrigin) *slack.ViewSubmissionResponse
+	// Optionally handle the modal being closed, which requires NotifyOnClose on the view
+	OnClose func(bot *Bot, interaction slack.InteractionCallback, origin ShortcutOrigin)
+}
+
+// Register a global shortcut which opens a modal and handles its submission
+func (b *Bot) RegisterShortcutModal(callbackId string, modal ShortcutModal) Handle {
+	return b.registerShortcutModal(slack.InteractionTypeShortcut, callbackId, modal)
+}
+
+// Register a message shortcut which opens a modal and handles its submission. The channel and message the shortcut
+// was used on are passed to OnSubmit in the ShortcutOrigin.
+func (b *Bot) RegisterMessageShortcutModal(callbackId string, modal ShortcutModal) Handle {
+	return b.registerShortcutModal(slack.InteractionTypeMessageAction, callbackId, modal)
+}
+
+func (b *Bot) registerShortcutModal(shortcutType slack.InteractionType, callbackId string, modal ShortcutModal) Handle {
+	description := "modal callback_id=" + callbackId
+	group := map[slack.InteractionType]interactiveRegistration{
+		shortcutType: {
+			description: description,
+			match:       matchCallbackID(callbackId),
+			callback: func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+				b.openShortcutModal(ctx, callbackId, modal, interaction)
+				return nil
+			},
+		},
+		slack.InteractionTypeViewSubmission: {
+			description: description,
+			match:       matchViewCallbackID(callbackId),
+			callback: func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+				origin := b.restoreShortcutOrigin(&interaction)
+				if modal.OnSubmit == nil {
+					return nil
+				}
+				return modal.OnSubmit(b, interaction, origin)
+			},
+		},
+	}
+	if modal.OnClose != nil {
+		group[slack.InteractionTypeViewClosed] = interactiveRegistration{
+			description: description,
+			match:       matchViewCallbackID(callbackId),
+			callback: func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) (response interface{}) {
+				origin := b.restoreShortcutOrigin(&interaction)
+				modal.OnClose(b, interaction, origin)
+				return nil
+			},
+		}
+	}
+	return b.registerInteractiveGroup(group)
+}
+
+func (b *Bot) openShortcutModal(ctx context.Context, callbackId string, modal ShortcutModal, interaction slack.InteractionCallback) {
+	view := modal.View(b, interaction)
+	view.Type = slack.VTModal
+	view.CallbackID = callbackId
+
+	origin := ShortcutOrigin{
+		Type:       interaction.Type,
+		CallbackID: callbackId,
+		TeamID:     interaction.Team.ID,
+		UserID:     interaction.User.ID,
+		Metadata:   view.PrivateMetadata,
+	}
+	if interaction.Type == slack.InteractionTypeMessageAction {
+		origin.ChannelID = interaction.Channel.ID
+		origin.MessageTs = interaction.Message.Timestamp
+		origin.ThreadTs = interaction.Message.ThreadTimestamp
+	}
+	metadata, err := json.Marshal(origin)
+	if err != nil {
+		b.logger().Error("Failed to encode shortcut origin", Fields{"callback_id": callbackId, "error": err})
+		return
+	}
+	view.PrivateMetadata = string(metadata)
+
+	if _, err := b.ApiForTeam(interaction.Team.ID).OpenViewContext(ctx, interaction.TriggerID, view); err != nil {
+		b.logger().Error("Failed to open shortcut modal", Fields{"callback_id": callbackId, "error": err})
+	}
+}
+
+// restoreShortcutOrigin decodes the origin from a view's private_metadata and puts back the metadata set by ShortcutModal.View
+func (b *Bot) restoreShortcutOrigin(interaction *slack.InteractionCallback) ShortcutOrigin {
+	var origin ShortcutOrigin
+	if err := json.Unmarshal([]byte(interaction.View.PrivateMetadata), &origin); err != nil {
+		b.logger().Warn("Failed to decode shortcut origin", Fields{"callback_id": interaction.View.CallbackID, "error": err})
+		return origin
+	}
+	interaction.View.PrivateMetadata = origin.Metadata
+	return origin
+}
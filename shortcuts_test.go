@@ -0,0 +1,139 @@
+package slackbot
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type openViewRequest struct {
+	TriggerID string                 `json:"trigger_id"`
+	View      slack.ModalViewRequest `json:"view"`
+}
+
+// openedViews decodes the views.open calls a fake received
+func openedViews(server *fakeSlack) []openViewRequest {
+	var opened []openViewRequest
+	for _, call := range server.callsTo("views.open") {
+		var request openViewRequest
+		_ = json.Unmarshal(call.body, &request)
+		opened = append(opened, request)
+	}
+	return opened
+}
+
+func TestRegisterMessageShortcutModal(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	var submittedOrigin ShortcutOrigin
+	var submittedMetadata string
+	bot.RegisterMessageShortcutModal("quote", ShortcutModal{
+		View: func(bot *Bot, interaction slack.InteractionCallback) slack.ModalViewRequest {
+			return slack.ModalViewRequest{
+				Title:           slack.NewTextBlockObject(slack.PlainTextType, "Quote", false, false),
+				PrivateMetadata: "feature state",
+				Blocks: slack.Blocks{BlockSet: []slack.Block{
+					slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, interaction.Message.Text, false, false), nil, nil),
+				}},
+			}
+		},
+		OnSubmit: func(bot *Bot, interaction slack.InteractionCallback, origin ShortcutOrigin) *slack.ViewSubmissionResponse {
+			submittedOrigin = origin
+			submittedMetadata = interaction.View.PrivateMetadata
+			return nil
+		},
+	})
+	bot.prepareEngine(engine, false)
+
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type:       slack.InteractionTypeMessageAction,
+		CallbackID: "quote",
+		TriggerID:  "trigger",
+		Team:       slack.Team{ID: "T1"},
+		User:       slack.User{ID: "U1"},
+		Channel:    slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}},
+		Message:    slack.Message{Msg: slack.Msg{Text: "quote me", Timestamp: "1.1"}},
+	})
+
+	opened := openedViews(server)
+	if assert.Equal(t, 1, len(opened)) {
+		assert.Equal(t, "trigger", opened[0].TriggerID)
+		assert.Equal(t, slack.VTModal, opened[0].View.Type)
+		assert.Equal(t, "quote", opened[0].View.CallbackID)
+		assert.Contains(t, opened[0].View.PrivateMetadata, `"channel_id":"C1"`)
+	}
+
+	postInteraction(t, engine, slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		View: slack.View{CallbackID: "quote", PrivateMetadata: opened[0].View.PrivateMetadata},
+	})
+
+	assert.Equal(t, ShortcutOrigin{
+		Type:       slack.InteractionTypeMessageAction,
+		CallbackID: "quote",
+		TeamID:     "T1",
+		UserID:     "U1",
+		ChannelID:  "C1",
+		MessageTs:  "1.1",
+		Metadata:   "feature state",
+	}, submittedOrigin)
+	assert.Equal(t, "feature state", submittedMetadata)
+}
+
+func TestRegisterShortcutModalSubmissionResponse(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterShortcutModal("create", ShortcutModal{
+		View: func(bot *Bot, interaction slack.InteractionCallback) slack.ModalViewRequest {
+			return slack.ModalViewRequest{CallbackID: "ignored"}
+		},
+		OnSubmit: func(bot *Bot, interaction slack.InteractionCallback, origin ShortcutOrigin) *slack.ViewSubmissionResponse {
+			return slack.NewErrorsViewSubmissionResponse(map[string]string{"title": "required"})
+		},
+	})
+	bot.prepareEngine(engine, false)
+
+	postInteraction(t, engine, slack.InteractionCallback{Type: slack.InteractionTypeShortcut, CallbackID: "create", TriggerID: "trigger"})
+	opened := openedViews(server)
+	assert.Equal(t, slack.VTModal, opened[0].View.Type)
+	assert.Equal(t, "create", opened[0].View.CallbackID)
+
+	payload, _ := json.Marshal(slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		View: slack.View{CallbackID: "create", PrivateMetadata: opened[0].View.PrivateMetadata},
+	})
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("response_action", "errors")
+}
+
+func TestUnregisterShortcutModal(t *testing.T) {
+	bot := newBot()
+
+	handle := bot.RegisterShortcutModal("create", ShortcutModal{
+		View: func(bot *Bot, interaction slack.InteractionCallback) slack.ModalViewRequest {
+			return slack.ModalViewRequest{}
+		},
+		OnClose: func(bot *Bot, interaction slack.InteractionCallback, origin ShortcutOrigin) {},
+	})
+	assert.Equal(t, 1, len(bot.interactives[slack.InteractionTypeShortcut]))
+	assert.Equal(t, 1, len(bot.interactives[slack.InteractionTypeViewSubmission]))
+	assert.Equal(t, 1, len(bot.interactives[slack.InteractionTypeViewClosed]))
+
+	bot.UnregisterInteraction(handle)
+	assert.Equal(t, 0, len(bot.interactives[slack.InteractionTypeShortcut]))
+	assert.Equal(t, 0, len(bot.interactives[slack.InteractionTypeViewSubmission]))
+	assert.Equal(t, 0, len(bot.interactives[slack.InteractionTypeViewClosed]))
+}
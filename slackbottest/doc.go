@@ -0,0 +1,7 @@
+/*
+Package slackbottest provides utilities for testing bots built with slackbot fully offline.
+
+It can build signed slash command, event and interaction requests to serve through Bot.Handler,
+and run a fake Slack Web API server that records every call made through Bot.Api.
+*/
+package slackbottest
@@ -0,0 +1,175 @@
+package slackbottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bushelpowered/slackbot"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A single Web API call received by a Server
+type Call struct {
+	Method string
+	Token  string
+	Values url.Values
+	Body   []byte
+}
+
+// Server is a fake Slack Web API which records calls and answers them with canned responses
+type Server struct {
+	*httptest.Server
+
+	calls     []Call
+	responses map[string]interface{}
+	lastTs    int
+
+	sync.Mutex
+}
+
+// Start a new fake Slack Web API server. Call Close when finished.
+func NewServer() *Server {
+	s := &Server{responses: make(map[string]interface{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Get the base URL of the fake Web API, suitable for slackbot.OptionAPIURL
+func (s *Server) APIURL() string {
+	return s.URL + "/api/"
+}
+
+// Create a bot using Token and SigningSecret whose Bot.Api calls are sent to this server
+func (s *Server) NewBot(options ...slackbot.Option) *slackbot.Bot {
+	options = append([]slackbot.Option{slackbot.OptionAPIURL(s.APIURL())}, options...)
+	return slackbot.NewBot(Token, SigningSecret, options...)
+}
+
+// Set the JSON response for a Web API method, e.g. "users.info".
+// Methods without a response answer {"ok": true} along with the channel and a generated ts.
+func (s *Server) SetResponse(method string, response interface{}) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.responses[method] = response
+}
+
+// Get the recorded calls to a Web API method, or all calls when method is empty
+func (s *Server) Calls(method string) []Call {
+	s.Lock()
+	defer s.Unlock()
+
+	calls := make([]Call, 0)
+	for _, call := range s.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Forget all recorded calls
+func (s *Server) Reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.calls = nil
+}
+
+// Check whether chat.postMessage was called with the given channel and text
+func (s *Server) PostedMessage(channel string, text string) bool {
+	for _, call := range s.Calls("chat.postMessage") {
+		if call.Values.Get("channel") == channel && call.Values.Get("text") == text {
+			return true
+		}
+	}
+	return false
+}
+
+// Assert that the bot posted a message with the given text in the given channel
+func (s *Server) AssertPostedMessage(t testing.TB, channel string, text string) bool {
+	t.Helper()
+
+	if !s.PostedMessage(channel, text) {
+		t.Errorf("expected message %q to be posted in channel %s, got calls: %v", text, channel, s.Calls("chat.postMessage"))
+		return false
+	}
+	return true
+}
+
+// Assert that the bot called a Web API method at least once
+func (s *Server) AssertCalled(t testing.TB, method string) bool {
+	t.Helper()
+
+	if len(s.Calls(method)) == 0 {
+		t.Errorf("expected %s to be called", method)
+		return false
+	}
+	return true
+}
+
+// Assert that the bot never called a Web API method
+func (s *Server) AssertNotCalled(t testing.TB, method string) bool {
+	t.Helper()
+
+	if calls := s.Calls(method); len(calls) > 0 {
+		t.Errorf("expected %s not to be called, got calls: %v", method, calls)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	call := Call{Method: method, Body: body, Values: url.Values{}}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err == nil {
+			for key, value := range fields {
+				var str string
+				if json.Unmarshal(value, &str) == nil {
+					call.Values.Set(key, str)
+				} else {
+					call.Values.Set(key, string(value))
+				}
+			}
+		}
+	} else if values, err := url.ParseQuery(string(body)); err == nil {
+		call.Values = values
+	}
+	for key, values := range r.URL.Query() {
+		call.Values[key] = values
+	}
+	call.Token = call.Values.Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		call.Token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	s.Lock()
+	s.calls = append(s.calls, call)
+	response, exists := s.responses[method]
+	if !exists {
+		s.lastTs++
+		response = map[string]interface{}{
+			"ok":      true,
+			"channel": call.Values.Get("channel"),
+			"ts":      fmt.Sprintf("1500000000.%06d", s.lastTs),
+		}
+	}
+	s.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,111 @@
+package slackbottest_test
+
+import (
+	"github.com/bushelpowered/slackbot"
+	"github.com/bushelpowered/slackbot/slackbottest"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCommandRequestPostsMessage(t *testing.T) {
+	server := slackbottest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	bot.RegisterCommand("hello", func(bot *slackbot.Bot, command slack.SlashCommand) *slack.Msg {
+		_, _, err := bot.Api().PostMessage(command.ChannelID, slack.MsgOptionText("hello "+command.Text, false))
+		assert.NoError(t, err)
+		return nil
+	})
+
+	request := slackbottest.NewCommandRequest(slackbottest.SigningSecret, slack.SlashCommand{
+		Command:   "/hello",
+		Text:      "world",
+		ChannelID: "C1",
+	})
+	response := slackbottest.Serve(bot.Handler(), request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	server.AssertPostedMessage(t, "C1", "hello world")
+	server.AssertNotCalled(t, "chat.postEphemeral")
+	assert.Equal(t, slackbottest.Token, server.Calls("chat.postMessage")[0].Token)
+}
+
+func TestUnsignedRequestIsRejected(t *testing.T) {
+	server := slackbottest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	bot.RegisterCommand("hello", func(bot *slackbot.Bot, command slack.SlashCommand) *slack.Msg {
+		return nil
+	})
+
+	request := slackbottest.NewCommandRequest("wrong secret", slack.SlashCommand{Command: "/hello"})
+	response := slackbottest.Serve(bot.Handler(), request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestEventRequest(t *testing.T) {
+	server := slackbottest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	var received slackbot.MessageEventContainer
+	bot.RegisterMessageEvent(func(bot *slackbot.Bot, c slackbot.MessageEventContainer) {
+		received = c
+	})
+
+	request := slackbottest.NewEventRequest(slackbottest.SigningSecret, "T1", slackevents.MessageEvent{
+		Type:    slackevents.Message,
+		Channel: "C1",
+		Text:    "hi",
+	})
+	response := slackbottest.Serve(bot.Handler(), request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "T1", received.APIEvent.TeamID)
+	assert.Equal(t, "hi", received.Event.Text)
+}
+
+func TestInteractionRequest(t *testing.T) {
+	server := slackbottest.NewServer()
+	defer server.Close()
+
+	bot := server.NewBot()
+	hit := false
+	bot.RegisterShortcutInteraction("shortcut1", func(bot *slackbot.Bot, interaction slack.InteractionCallback) {
+		hit = true
+	})
+
+	request := slackbottest.NewInteractionRequest(slackbottest.SigningSecret, slack.InteractionCallback{
+		Type:       slack.InteractionTypeShortcut,
+		CallbackID: "shortcut1",
+	})
+	response := slackbottest.Serve(bot.Handler(), request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, hit)
+}
+
+func TestSetResponse(t *testing.T) {
+	server := slackbottest.NewServer()
+	defer server.Close()
+
+	server.SetResponse("users.info", map[string]interface{}{
+		"ok":   true,
+		"user": map[string]interface{}{"id": "U1", "name": "alice"},
+	})
+
+	user, err := server.NewBot().Api().GetUserInfo("U1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	assert.Equal(t, "U1", server.Calls("users.info")[0].Values.Get("user"))
+
+	server.Reset()
+	assert.Empty(t, server.Calls(""))
+}
@@ -0,0 +1,105 @@
+package slackbot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Persists state shared by the replicas of a bot, such as scheduler leadership. Implementations must be safe for
+// concurrent use.
+type Storage interface {
+	// Get the value of a key, or ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set the value of a key
+	Set(ctx context.Context, key string, value []byte) error
+	// Delete a key, which is not an error when it does not exist
+	Delete(ctx context.Context, key string) error
+	// List the keys and values whose key starts with prefix
+	List(ctx context.Context, prefix string) (map[string][]byte, error)
+	// Acquire a lock named key for owner, or extend it when owner already holds it, until ttl elapses.
+	// Returns false when another owner holds an unexpired lock.
+	Lock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+	// Release a lock held by owner before it expires
+	Unlock(ctx context.Context, key string, owner string) error
+}
+
+type memoryLock struct {
+	owner   string
+	expires time.Time
+}
+
+// Storage keeping state in process memory, for bots running a single replica and for tests
+type MemoryStorage struct {
+	values map[string][]byte
+	locks  map[string]memoryLock
+	mutex  sync.Mutex
+}
+
+// Create a new empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{values: make(map[string][]byte), locks: make(map[string]memoryLock)}
+}
+
+func (s *MemoryStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, exists := s.values[key]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (s *MemoryStorage) Set(ctx context.Context, key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+func (s *MemoryStorage) List(ctx context.Context, prefix string) (map[string][]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values := make(map[string][]byte)
+	for key, value := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			values[key] = append([]byte(nil), value...)
+		}
+	}
+	return values, nil
+}
+
+func (s *MemoryStorage) Lock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if lock, exists := s.locks[key]; exists && lock.owner != owner && now.Before(lock.expires) {
+		return false, nil
+	}
+	s.locks[key] = memoryLock{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStorage) Unlock(ctx context.Context, key string, owner string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.locks[key].owner == owner {
+		delete(s.locks, key)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage()
+
+	_, err := storage.Get(ctx, "a")
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.NoError(t, storage.Set(ctx, "a/1", []byte("one")))
+	assert.NoError(t, storage.Set(ctx, "a/2", []byte("two")))
+	assert.NoError(t, storage.Set(ctx, "b/1", []byte("three")))
+
+	value, err := storage.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("one"), value)
+
+	values, err := storage.List(ctx, "a/")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a/1": []byte("one"), "a/2": []byte("two")}, values)
+
+	assert.NoError(t, storage.Delete(ctx, "a/1"))
+	assert.NoError(t, storage.Delete(ctx, "a/1"))
+	_, err = storage.Get(ctx, "a/1")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestMemoryStorageLock(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage()
+
+	locked, _ := storage.Lock(ctx, "leader", "a", time.Minute)
+	assert.True(t, locked)
+	locked, _ = storage.Lock(ctx, "leader", "b", time.Minute)
+	assert.False(t, locked)
+	// the owner extends its lock
+	locked, _ = storage.Lock(ctx, "leader", "a", time.Minute)
+	assert.True(t, locked)
+
+	// only the owner releases a lock
+	assert.NoError(t, storage.Unlock(ctx, "leader", "b"))
+	locked, _ = storage.Lock(ctx, "leader", "b", time.Minute)
+	assert.False(t, locked)
+	assert.NoError(t, storage.Unlock(ctx, "leader", "a"))
+	locked, _ = storage.Lock(ctx, "leader", "b", time.Minute)
+	assert.True(t, locked)
+
+	// an expired lock is taken over
+	locked, _ = storage.Lock(ctx, "expiring", "a", time.Millisecond)
+	assert.True(t, locked)
+	time.Sleep(5 * time.Millisecond)
+	locked, _ = storage.Lock(ctx, "expiring", "b", time.Minute)
+	assert.True(t, locked)
+}
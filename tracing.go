@@ -0,0 +1,77 @@
+package slackbot
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+const tracerName = "github.com/bushelpowered/slackbot"
+
+var (
+	attributeMethod     = attribute.Key("slack.method")
+	attributeStatusCode = attribute.Key("http.status_code")
+)
+
+// Log fields which are also recorded as span attributes, prefixed with "slack."
+var spanAttributeFields = []string{"team_id", "user_id", "channel_id", "command", "event_type", "event_id", "interaction_type", "callback_id"}
+
+// startSpan starts a server span for a dispatched request, parented to any span already on the request context
+func (b *Bot) startSpan(ctx *gin.Context, name string, fields Fields) (context.Context, trace.Span) {
+	attributes := make([]attribute.KeyValue, 0, len(spanAttributeFields))
+	for _, key := range spanAttributeFields {
+		if value, exists := fields[key]; exists {
+			attributes = append(attributes, attribute.Key("slack."+key).String(fmt.Sprint(value)))
+		}
+	}
+
+	return b.tracer.Start(ctx.Request.Context(), name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer trace.Tracer
+	apiURL string
+}
+
+func (t *tracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	method := apiMethod(request, t.apiURL)
+	ctx, span := t.tracer.Start(request.Context(), "slack "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributeMethod.String(method)),
+	)
+	defer span.End()
+
+	response, err := t.next.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return response, err
+	}
+
+	span.SetAttributes(attributeStatusCode.Int(response.StatusCode))
+	if response.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, response.Status)
+	}
+	return response, nil
+}
+
+// traceClient returns a copy of client whose requests are recorded as child spans of the request context, named after
+// the Web API methods under apiURL
+func traceClient(client *http.Client, tracer trace.Tracer, apiURL string) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	traced := *client
+	traced.Transport = &tracingTransport{next: transport, tracer: tracer, apiURL: apiURL}
+	return &traced
+}
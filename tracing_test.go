@@ -0,0 +1,116 @@
+package slackbot
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTracedBot(options ...Option) (*Bot, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return NewBot("token", "secret", append(options, OptionTracerProvider(provider))...), exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracingCommandWithApiCall(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot, exporter := newTracedBot(OptionAPIURL(server.URL + "/api/"))
+	bot.RegisterCommandContext("test", func(ctx context.Context, bot *Bot, command slack.SlashCommand) *slack.Msg {
+		assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+		_, err := bot.Api().AuthTestContext(ctx)
+		assert.NoError(t, err)
+		return nil
+	})
+
+	body := []byte("command=%2Ftest&team_id=T1&user_id=U1&channel_id=C1")
+	request := newSignedRequest(t, "secret", "/slack/commands", "application/x-www-form-urlencoded", body)
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+
+	apiSpan, commandSpan := spans[0], spans[1]
+	assert.Equal(t, "slack auth.test", apiSpan.Name)
+	assert.Equal(t, "auth.test", spanAttribute(apiSpan, attributeMethod))
+	assert.Equal(t, commandSpan.SpanContext.SpanID(), apiSpan.Parent.SpanID())
+
+	assert.Equal(t, "slackbot.command", commandSpan.Name)
+	assert.Equal(t, "T1", spanAttribute(commandSpan, attribute.Key("slack.team_id")))
+	assert.Equal(t, "U1", spanAttribute(commandSpan, attribute.Key("slack.user_id")))
+	assert.Equal(t, "C1", spanAttribute(commandSpan, attribute.Key("slack.channel_id")))
+	assert.Equal(t, "/test", spanAttribute(commandSpan, attribute.Key("slack.command")))
+}
+
+func TestTracingResponseURLPost(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+
+	bot, exporter := newTracedBot(OptionAPIURL(server.URL + "/api/"))
+	assert.NoError(t, bot.Respond(server.responseURL()+"/T1/1/secret", &slack.Msg{Text: "done"}))
+
+	spans := exporter.GetSpans()
+	if assert.Equal(t, 1, len(spans)) {
+		assert.Equal(t, "slack response_url", spans[0].Name)
+		assert.Equal(t, "response_url", spanAttribute(spans[0], attributeMethod))
+	}
+}
+
+func TestTracingEvent(t *testing.T) {
+	bot, exporter := newTracedBot()
+	var spanContext trace.SpanContext
+	bot.RegisterMessageEvent(func(bot *Bot, c MessageEventContainer) {
+		spanContext = trace.SpanContextFromContext(c.Context())
+	})
+
+	body := []byte(`{"type": "event_callback", "team_id": "T1", "event": {"type": "message", "user": "U1", "channel": "C1", "text": "hi"}}`)
+	request := newSignedRequest(t, "secret", "/slack/events", "application/json", body)
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spanContext.SpanID())
+	assert.Equal(t, "T1", spanAttribute(spans[0], attribute.Key("slack.team_id")))
+	assert.Equal(t, "U1", spanAttribute(spans[0], attribute.Key("slack.user_id")))
+	assert.Equal(t, "C1", spanAttribute(spans[0], attribute.Key("slack.channel_id")))
+	assert.Equal(t, slackevents.Message, spanAttribute(spans[0], attribute.Key("slack.event_type")))
+}
+
+func TestTracingInteraction(t *testing.T) {
+	bot, exporter := newTracedBot()
+	hit := false
+	bot.RegisterInteractionContext(slack.InteractionTypeShortcut, func(ctx context.Context, bot *Bot, interaction slack.InteractionCallback) interface{} {
+		hit = trace.SpanContextFromContext(ctx).IsValid()
+		return nil
+	})
+
+	body := []byte(`payload=%7B%22type%22%3A%22shortcut%22%2C%22callback_id%22%3A%22shortcut1%22%7D`)
+	request := newSignedRequest(t, "secret", "/slack/interactives", "application/x-www-form-urlencoded", body)
+	bot.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+	assert.True(t, hit)
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "shortcut1", spanAttribute(spans[0], attribute.Key("slack.callback_id")))
+}
+
+func TestEventContainerContextDefaultsToBackground(t *testing.T) {
+	assert.Equal(t, context.Background(), MessageEventContainer{}.Context())
+}
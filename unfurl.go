@@ -0,0 +1,292 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fetches the unfurl of a link. Return an attachment, setting only Blocks for a Block Kit unfurl, or nil to leave the link alone.
+type UnfurlCallback = func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error)
+
+// Options for OptionUnfurl
+type UnfurlOptions struct {
+	// Longest an UnfurlCallback may take for one link. Defaults to 5 seconds.
+	Timeout time.Duration
+	// Links of one event unfurled at the same time. Defaults to 4.
+	Concurrency int
+	// How long an unfurl is reused for the same link. Defaults to 10 minutes, a negative value disables caching.
+	CacheTTL time.Duration
+}
+
+type unfurler struct {
+	handle   Handle
+	pattern  string
+	callback UnfurlCallback
+}
+
+type cachedUnfurl struct {
+	attachment *slack.Attachment
+	expires    time.Time
+}
+
+type unfurlers struct {
+	options   UnfurlOptions
+	installed bool
+	unfurlers []unfurler
+	cache     map[string]cachedUnfurl
+	cacheLock sync.Mutex
+}
+
+func newUnfurlers(options UnfurlOptions) *unfurlers {
+	if options.Timeout == 0 {
+		options.Timeout = 5 * time.Second
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	if options.CacheTTL == 0 {
+		options.CacheTTL = 10 * time.Minute
+	}
+	return &unfurlers{options: options, cache: make(map[string]cachedUnfurl)}
+}
+
+// Register a callback unfurling links whose host matches domainPattern, either a host such as "example.com" or a
+// wildcard such as "*.example.com" matching its subdomains. The first matching unfurler handles a link. The domain
+// must also be configured as an unfurl domain of the Slack app.
+func (b *Bot) RegisterUnfurler(domainPattern string, callback UnfurlCallback) Handle {
+	b.logger().Debug("Registered unfurler", Fields{"domain": domainPattern})
+
+	b.Lock()
+	if b.unfurl == nil {
+		b.unfurl = newUnfurlers(UnfurlOptions{})
+	}
+	install := !b.unfurl.installed
+	b.unfurl.installed = true
+	handle := b.nextHandle()
+	b.unfurl.unfurlers = append(b.unfurl.unfurlers, unfurler{handle: handle, pattern: strings.ToLower(domainPattern), callback: callback})
+	b.Unlock()
+
+	if install {
+		b.registerEvent(slackevents.LinkShared, func(ctx context.Context, bot *Bot, event slackevents.EventsAPIEvent) {
+			raw, _ := ctx.Value(rawEventContextKey{}).(json.RawMessage)
+			var e linkSharedEvent
+			if err := json.Unmarshal(raw, &e); err != nil {
+				b.logger().Warn("Failed to parse link_shared event", Fields{"team_id": event.TeamID, "error": err})
+				return
+			}
+			b.goDetached(ctx, func(ctx context.Context) {
+				b.unfurlLinks(ctx, event.TeamID, e)
+			})
+		})
+	}
+	return handle
+}
+
+// linkSharedEvent is a link_shared event. slackevents.LinkSharedEvent cannot hold the message_ts of links shared in
+// the message composer, which is not a timestamp, nor their unfurl_id and source.
+type linkSharedEvent struct {
+	Channel   string `json:"channel"`
+	MessageTs string `json:"message_ts"`
+	UnfurlID  string `json:"unfurl_id"`
+	Source    string `json:"source"`
+	Links     []struct {
+		Domain string `json:"domain"`
+		URL    string `json:"url"`
+	} `json:"links"`
+}
+
+// Unregister an unfurler using the Handle returned when it was registered
+func (b *Bot) UnregisterUnfurler(handle Handle) {
+	b.logger().Debug("Unregistered unfurler", Fields{"handle": handle})
+
+	b.Lock()
+	defer b.Unlock()
+
+	if b.unfurl == nil {
+		return
+	}
+	for i, u := range b.unfurl.unfurlers {
+		if u.handle == handle {
+			b.unfurl.unfurlers = append(b.unfurl.unfurlers[:i:i], b.unfurl.unfurlers[i+1:]...)
+			return
+		}
+	}
+}
+
+// unfurler returns the first unfurler matching a link
+func (b *Bot) unfurler(link string) (UnfurlCallback, bool) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return nil, false
+	}
+	host := strings.ToLower(parsed.Hostname())
+
+	b.RLock()
+	defer b.RUnlock()
+
+	for _, u := range b.unfurl.unfurlers {
+		if matchDomain(u.pattern, host) {
+			return u.callback, true
+		}
+	}
+	return nil, false
+}
+
+func matchDomain(pattern string, host string) bool {
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return host == pattern
+}
+
+// unfurlLinks fetches the unfurls of every matched link of an event concurrently and sends them in a single chat.unfurl
+func (b *Bot) unfurlLinks(ctx context.Context, teamID string, event linkSharedEvent) {
+	options := b.unfurl.options
+
+	var (
+		unfurls = make(map[string]slack.Attachment)
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		slots   = make(chan struct{}, options.Concurrency)
+	)
+	for _, link := range event.Links {
+		callback, exists := b.unfurler(link.URL)
+		if !exists {
+			continue
+		}
+
+		wg.Add(1)
+		go func(link string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			attachment, err := b.fetchUnfurl(ctx, link, callback)
+			if err != nil {
+				b.logger().Warn("Failed to unfurl link", Fields{"url": link, "error": err})
+				return
+			}
+			if attachment != nil {
+				lock.Lock()
+				unfurls[link] = *attachment
+				lock.Unlock()
+			}
+		}(link.URL)
+	}
+	wg.Wait()
+
+	if len(unfurls) == 0 {
+		return
+	}
+	msgOptions := []slack.MsgOption{slack.MsgOptionUnfurl(event.MessageTs, unfurls)}
+	if event.UnfurlID != "" {
+		msgOptions = append(msgOptions, b.msgOptionUnfurlID(event.UnfurlID, event.Source))
+	}
+	_, _, _, err := b.ApiForTeam(teamID).SendMessageContext(ctx, event.Channel, msgOptions...)
+	if err != nil {
+		b.logger().Error("Failed to unfurl links", Fields{"team_id": teamID, "channel_id": event.Channel, "unfurl_id": event.UnfurlID, "error": err})
+	}
+}
+
+// msgOptionUnfurlID identifies the links of a chat.unfurl call by unfurl_id and source instead of channel and ts,
+// which links shared in the message composer need and slack.MsgOptionUnfurl does not support
+func (b *Bot) msgOptionUnfurlID(unfurlID string, source string) slack.MsgOption {
+	return slack.UnsafeMsgOptionEndpoint(b.webAPIURL()+"chat.unfurl", func(values url.Values) {
+		values.Del("channel")
+		values.Del("ts")
+		values.Set("unfurl_id", unfurlID)
+		values.Set("source", source)
+	})
+}
+
+func (b *Bot) fetchUnfurl(ctx context.Context, link string, callback UnfurlCallback) (*slack.Attachment, error) {
+	u := b.unfurl
+	if u.options.CacheTTL > 0 {
+		u.cacheLock.Lock()
+		cached, exists := u.cache[link]
+		u.cacheLock.Unlock()
+		if exists && time.Now().Before(cached.expires) {
+			return cached.attachment, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, u.options.Timeout)
+	defer cancel()
+
+	// a callback ignoring ctx must not hold up the other links of the event
+	type result struct {
+		attachment *slack.Attachment
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
+		attachment, err := callback(ctx, b, link)
+		done <- result{attachment: attachment, err: err}
+	}()
+	var attachment *slack.Attachment
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		attachment = r.attachment
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if u.options.CacheTTL > 0 {
+		u.cacheLock.Lock()
+		now := time.Now()
+		for cachedLink, cached := range u.cache {
+			if now.After(cached.expires) {
+				delete(u.cache, cachedLink)
+			}
+		}
+		u.cache[link] = cachedUnfurl{attachment: attachment, expires: now.Add(u.options.CacheTTL)}
+		u.cacheLock.Unlock()
+	}
+	return attachment, nil
+}
+
+// detachedContext keeps the values of a context, such as its trace span, without its cancellation
+type detachedContext struct {
+	context.Context
+	values context.Context
+}
+
+func detachContext(ctx context.Context) context.Context {
+	return detachedContext{Context: context.Background(), values: ctx}
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// goDetached runs work in the background with the values of a request's context, after the request is answered
+func (b *Bot) goDetached(ctx context.Context, work func(ctx context.Context)) {
+	ctx = detachContext(ctx)
+	b.detached.Add(1)
+	go func() {
+		defer b.detached.Done()
+		work(ctx)
+	}()
+}
+
+// waitDetached waits for the work started with goDetached until ctx is done
+func (b *Bot) waitDetached(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		b.detached.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
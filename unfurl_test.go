@@ -0,0 +1,180 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func postLinkShared(t *testing.T, engine *gin.Engine, links ...string) {
+	shared := make([]map[string]string, len(links))
+	for i, link := range links {
+		parsed, _ := url.Parse(link)
+		shared[i] = map[string]string{"domain": parsed.Hostname(), "url": link}
+	}
+
+	postLinkSharedEvent(t, engine, map[string]interface{}{
+		"type":       slackevents.LinkShared,
+		"channel":    "C1",
+		"message_ts": "1.1",
+		"links":      shared,
+	})
+}
+
+func postLinkSharedEvent(t *testing.T, engine *gin.Engine, event map[string]interface{}) {
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/events").
+		WithJSON(fakeEvent{Type: slackevents.CallbackEvent, Event: event}).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func receiveUnfurl(t *testing.T, unfurled <-chan apiCall) map[string]slack.Attachment {
+	select {
+	case call := <-unfurled:
+		form := call.form
+		assert.Equal(t, "C1", form.Get("channel"))
+		assert.Equal(t, "1.1", form.Get("ts"))
+		var unfurls map[string]slack.Attachment
+		assert.NoError(t, json.Unmarshal([]byte(form.Get("unfurls")), &unfurls))
+		return unfurls
+	case <-time.After(time.Second):
+		t.Fatal("chat.unfurl was not called")
+		return nil
+	}
+}
+
+func TestRegisterUnfurlerBatchesLinks(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	unfurled := server.notify("chat.unfurl")
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterUnfurler("*.example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		return &slack.Attachment{Title: link}, nil
+	})
+	bot.RegisterUnfurler("example.org", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		return nil, errors.New("not found")
+	})
+	bot.prepareEngine(engine, false)
+
+	postLinkShared(t, engine, "https://a.example.com/1", "https://b.example.com/2", "https://example.org/3", "https://example.net/4")
+
+	unfurls := receiveUnfurl(t, unfurled)
+	assert.Equal(t, 2, len(unfurls))
+	assert.Equal(t, "https://a.example.com/1", unfurls["https://a.example.com/1"].Title)
+	assert.Equal(t, "https://b.example.com/2", unfurls["https://b.example.com/2"].Title)
+}
+
+func TestUnfurlerCachesResults(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	unfurled := server.notify("chat.unfurl")
+
+	engine := gin.New()
+	bot := server.newBot()
+	var fetches int32
+	bot.RegisterUnfurler("example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		atomic.AddInt32(&fetches, 1)
+		return &slack.Attachment{Title: "cached"}, nil
+	})
+	bot.prepareEngine(engine, false)
+
+	postLinkShared(t, engine, "https://example.com/1")
+	receiveUnfurl(t, unfurled)
+	postLinkShared(t, engine, "https://example.com/1")
+	unfurls := receiveUnfurl(t, unfurled)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	assert.Equal(t, "cached", unfurls["https://example.com/1"].Title)
+}
+
+func TestUnfurlerTimeout(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	unfurled := server.notify("chat.unfurl")
+
+	engine := gin.New()
+	bot := server.newBot(OptionUnfurl(UnfurlOptions{Timeout: 10 * time.Millisecond}))
+	bot.RegisterUnfurler("slow.example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		time.Sleep(time.Second)
+		return &slack.Attachment{Title: "too late"}, nil
+	})
+	bot.RegisterUnfurler("fast.example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		return &slack.Attachment{Title: "fast"}, nil
+	})
+	bot.prepareEngine(engine, false)
+
+	postLinkShared(t, engine, "https://slow.example.com/", "https://fast.example.com/")
+
+	unfurls := receiveUnfurl(t, unfurled)
+	assert.Equal(t, 1, len(unfurls))
+	assert.Equal(t, "fast", unfurls["https://fast.example.com/"].Title)
+}
+
+func TestUnfurlComposerLinks(t *testing.T) {
+	server := newFakeSlack()
+	defer server.Close()
+	unfurled := server.notify("chat.unfurl")
+
+	engine := gin.New()
+	bot := server.newBot()
+	bot.RegisterUnfurler("example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		return &slack.Attachment{Title: "composed"}, nil
+	})
+	bot.prepareEngine(engine, false)
+
+	// links in the message composer have no message yet
+	postLinkSharedEvent(t, engine, map[string]interface{}{
+		"type":       slackevents.LinkShared,
+		"user":       "U1",
+		"channel":    "COMPOSER",
+		"message_ts": "U1-909b5454-75f8-4ac4-b325-1b40e230bbd8-gryl3kb80b3wm49ihzoo35fyqoq08n2y",
+		"unfurl_id":  "C1.1600000000.abcd",
+		"source":     "composer",
+		"links":      []map[string]string{{"domain": "example.com", "url": "https://example.com/1"}},
+	})
+
+	select {
+	case call := <-unfurled:
+		assert.Equal(t, "C1.1600000000.abcd", call.form.Get("unfurl_id"))
+		assert.Equal(t, "composer", call.form.Get("source"))
+		assert.NotContains(t, call.form, "channel")
+		assert.NotContains(t, call.form, "ts")
+		assert.Contains(t, call.form.Get("unfurls"), `"title":"composed"`)
+	case <-time.After(time.Second):
+		t.Fatal("chat.unfurl was not called")
+	}
+}
+
+func TestUnregisterUnfurler(t *testing.T) {
+	bot := newBot()
+
+	handle := bot.RegisterUnfurler("example.com", func(ctx context.Context, bot *Bot, link string) (*slack.Attachment, error) {
+		return nil, nil
+	})
+	_, exists := bot.unfurler("https://example.com/")
+	assert.True(t, exists)
+
+	bot.UnregisterUnfurler(handle)
+	_, exists = bot.unfurler("https://example.com/")
+	assert.False(t, exists)
+}
+
+func TestMatchDomain(t *testing.T) {
+	assert.True(t, matchDomain("example.com", "example.com"))
+	assert.False(t, matchDomain("example.com", "www.example.com"))
+	assert.True(t, matchDomain("*.example.com", "www.example.com"))
+	assert.False(t, matchDomain("*.example.com", "example.com"))
+	assert.False(t, matchDomain("*.example.com", "badexample.com"))
+}
@@ -1,10 +1,24 @@
 package slackbot
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/slack-go/slack"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func getHttpExpect(t *testing.T, engine *gin.Engine) *httpexpect.Expect {
@@ -19,3 +33,187 @@ func getHttpExpect(t *testing.T, engine *gin.Engine) *httpexpect.Expect {
 		},
 	})
 }
+
+func newSignedRequest(t *testing.T, signingSecret string, path string, contentType string, body []byte) *http.Request {
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+
+	hash := hmac.New(sha256.New, []byte(signingSecret))
+	hash.Write([]byte(fmt.Sprintf("v0:%s:", timestamp)))
+	hash.Write(body)
+
+	request, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	request.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(hash.Sum(nil)))
+	return request
+}
+
+func postInteraction(t *testing.T, engine *gin.Engine, interaction slack.InteractionCallback) {
+	payload, err := json.Marshal(interaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := getHttpExpect(t, engine)
+	e.POST("/slack/interactives").
+		WithFormField("payload", string(payload)).
+		Expect().
+		Status(http.StatusOK)
+}
+
+// apiCall is a request received by fakeSlack
+type apiCall struct {
+	method string
+	token  string
+	// arguments of form and JSON encoded calls
+	form url.Values
+	body []byte
+}
+
+// fakeSlack is a fake Slack Web API for tests inside the package, which cannot import slackbottest. It records
+// every call and answers Web API methods with {"ok": true}, a channel and a generated ts unless told otherwise.
+// Messages posted to its responseURL are recorded as calls to "response".
+type fakeSlack struct {
+	*httptest.Server
+
+	calls     []apiCall
+	responses map[string]func(call apiCall) interface{}
+	handlers  map[string]http.HandlerFunc
+	notified  map[string]chan apiCall
+	lastTs    int
+	sync.Mutex
+}
+
+func newFakeSlack() *fakeSlack {
+	s := &fakeSlack{
+		responses: make(map[string]func(call apiCall) interface{}),
+		handlers:  make(map[string]http.HandlerFunc),
+		notified:  make(map[string]chan apiCall),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// newBot creates a bot whose Web API calls are sent to the fake
+func (s *fakeSlack) newBot(options ...Option) *Bot {
+	options = append([]Option{OptionAPIURL(s.URL + "/api/")}, options...)
+	return NewBot("token", "secret", options...)
+}
+
+func (s *fakeSlack) responseURL() string {
+	return s.URL + "/response"
+}
+
+// setResponse answers a method with a JSON response
+func (s *fakeSlack) setResponse(method string, response interface{}) {
+	s.setResponseFunc(method, func(call apiCall) interface{} {
+		return response
+	})
+}
+
+// setResponseFunc answers a method with a JSON response built from each call
+func (s *fakeSlack) setResponseFunc(method string, response func(call apiCall) interface{}) {
+	s.Lock()
+	defer s.Unlock()
+	s.responses[method] = response
+}
+
+// setHandler answers a method with handler once the call is recorded, for responses other than JSON
+func (s *fakeSlack) setHandler(method string, handler http.HandlerFunc) {
+	s.Lock()
+	defer s.Unlock()
+	s.handlers[method] = handler
+}
+
+// notify returns a channel receiving every later call to a method, for calls made in the background
+func (s *fakeSlack) notify(method string) <-chan apiCall {
+	s.Lock()
+	defer s.Unlock()
+	if s.notified[method] == nil {
+		s.notified[method] = make(chan apiCall, 10)
+	}
+	return s.notified[method]
+}
+
+// callsTo returns the recorded calls to a method, or all calls when method is empty
+func (s *fakeSlack) callsTo(method string) []apiCall {
+	s.Lock()
+	defer s.Unlock()
+
+	calls := make([]apiCall, 0)
+	for _, call := range s.calls {
+		if method == "" || call.method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// responded returns the messages posted to responseURL
+func (s *fakeSlack) responded() []slack.Msg {
+	var messages []slack.Msg
+	for _, call := range s.callsTo("response") {
+		var msg slack.Msg
+		_ = json.Unmarshal(call.body, &msg)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func (s *fakeSlack) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	call := apiCall{method: path.Base(r.URL.Path), body: body, form: r.URL.Query()}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var fields map[string]json.RawMessage
+		_ = json.Unmarshal(body, &fields)
+		for key, value := range fields {
+			var str string
+			if json.Unmarshal(value, &str) == nil {
+				call.form.Set(key, str)
+			} else {
+				call.form.Set(key, string(value))
+			}
+		}
+	} else if values, err := url.ParseQuery(string(body)); err == nil {
+		for key, value := range values {
+			call.form[key] = value
+		}
+	}
+	call.token = call.form.Get("token")
+	if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		call.token = strings.TrimPrefix(authorization, "Bearer ")
+	}
+
+	s.Lock()
+	s.calls = append(s.calls, call)
+	handler := s.handlers[call.method]
+	response := s.responses[call.method]
+	if response == nil {
+		s.lastTs++
+		ts := fmt.Sprintf("1500000000.%06d", s.lastTs)
+		response = func(call apiCall) interface{} {
+			return map[string]interface{}{"ok": true, "channel": call.form.Get("channel"), "ts": ts, "message_ts": ts}
+		}
+	}
+	notified := s.notified[call.method]
+	s.Unlock()
+
+	if notified != nil {
+		notified <- call
+	}
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response(call))
+}
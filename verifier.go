@@ -35,6 +35,7 @@ func (b *Bot) newSlackVerifierMiddleware() gin.HandlerFunc {
 			_ = c.AbortWithError(http.StatusUnauthorized, err)
 			return
 		}
+		c.Set(verifiedContextKey, true)
 
 		c.Next()
 	}